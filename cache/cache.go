@@ -0,0 +1,8 @@
+package cache
+
+// Cache is a minimal key-value store interface. It's intentionally byte oriented
+// so that an out of process implementation (e.g. Redis-compatible) can be plugged in
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// LRU is a thread safe in-process cache which evicts least recently used entries. Zero size disables caching.
+type LRU struct {
+	size  int
+	list  *list.List
+	items map[string]*list.Element
+	mtx   sync.Mutex
+}
+
+// NewLRU returns new LRU cache holding at most size entries
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:  size,
+		list:  list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (l *LRU) Get(key string) ([]byte, bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if e, ok := l.items[key]; ok {
+		l.list.MoveToFront(e)
+		return e.Value.(*lruEntry).value, true
+	}
+	return nil, false
+}
+
+func (l *LRU) Set(key string, value []byte) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.size <= 0 {
+		return
+	}
+
+	if e, ok := l.items[key]; ok {
+		l.list.MoveToFront(e)
+		e.Value.(*lruEntry).value = value
+		return
+	}
+
+	l.items[key] = l.list.PushFront(&lruEntry{key: key, value: value})
+	l.evict()
+}
+
+func (l *LRU) evict() {
+	for l.list.Len() > l.size {
+		e := l.list.Back()
+		l.list.Remove(e)
+		delete(l.items, e.Value.(*lruEntry).key)
+	}
+}
+
+// Resize changes the maximum number of entries evicting extra ones
+func (l *LRU) Resize(size int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.size = size
+	if l.size < 0 {
+		l.size = 0
+	}
+	l.evict()
+}
+
+// Len returns the number of cached entries
+func (l *LRU) Len() int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.list.Len()
+}
+
+// Purge drops all entries
+func (l *LRU) Purge() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.list.Init()
+	l.items = make(map[string]*list.Element)
+}
+
+var _ Cache = &LRU{}
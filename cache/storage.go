@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// Storage caches results of the underlying storage queries. Cache keys include the chain head generation
+// so all entries become unreachable as soon as a new head or reorganization is detected.
+// Results which can't be affected by new blocks (those below the final block) are keyed independently of the head
+// and live until evicted.
+type Storage struct {
+	storage.Storage
+	Cache Cache
+
+	hits   uint64
+	misses uint64
+
+	mtx        sync.RWMutex
+	generation uint64 // Incremented on every head change
+	epoch      uint64 // Incremented on reorganization deeper than the final block
+	final      *storage.Block
+}
+
+// Stats contains cache hit/miss counters
+type Stats struct {
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Generation uint64 `json:"generation"`
+	Epoch      uint64 `json:"epoch"`
+}
+
+// Stats returns cache statistics
+func (s *Storage) Stats() *Stats {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return &Stats{
+		Hits:       atomic.LoadUint64(&s.hits),
+		Misses:     atomic.LoadUint64(&s.misses),
+		Generation: s.generation,
+		Epoch:      s.epoch,
+	}
+}
+
+// Update invalidates head dependent entries. final is the most recent block considered to be immutable.
+// If reorg is true the final block itself has been replaced and all entries are invalidated.
+func (s *Storage) Update(final *storage.Block, reorg bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.generation++
+	if reorg {
+		s.epoch++
+	}
+	s.final = final
+}
+
+// key returns a cache key. Keys of final results don't depend on the head generation.
+func (s *Storage) key(isFinal func(final *storage.Block) bool, format string, args ...interface{}) string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	k := fmt.Sprintf(format, args...)
+	if s.final != nil && isFinal(s.final) {
+		return fmt.Sprintf("e%d:%s", s.epoch, k)
+	}
+	return fmt.Sprintf("e%d:g%d:%s", s.epoch, s.generation, k)
+}
+
+func (s *Storage) get(key string, v interface{}) bool {
+	if buf, ok := s.Cache.Get(key); ok && json.Unmarshal(buf, v) == nil {
+		atomic.AddUint64(&s.hits, 1)
+		return true
+	}
+	atomic.AddUint64(&s.misses, 1)
+	return false
+}
+
+func (s *Storage) set(key string, v interface{}) {
+	if buf, err := json.Marshal(v); err == nil {
+		s.Cache.Set(key, buf)
+	}
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func (s *Storage) GetBalanceUpdate(ctx context.Context, address string, start, end time.Time, limit int) ([]*storage.BalanceUpdate, error) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	isFinal := func(final *storage.Block) bool {
+		return !end.IsZero() && !end.After(final.Timestamp)
+	}
+
+	key := s.key(isFinal, "balance:%s:%d:%d:%d", address, unixNano(start), unixNano(end), limit)
+
+	var res []*storage.BalanceUpdate
+	if s.get(key, &res) {
+		return res, nil
+	}
+
+	res, err := s.Storage.GetBalanceUpdate(ctx, address, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.set(key, res)
+	return res, nil
+}
+
+var _ storage.Storage = &Storage{}
@@ -66,6 +66,8 @@ var (
 	CodeForbidden        Code = stdCode("forbidden")
 	CodeEndpointNotFound Code = stdCode("endpoint_not_found")
 	CodeLimitTooBig      Code = stdCode("limit_too_big")
+	CodeQueryTimeout     Code = stdCode("query_timeout")
+	CodeNotReady         Code = stdCode("not_ready")
 )
 
 var httpStatus = map[stdCode]int{
@@ -76,6 +78,23 @@ var httpStatus = map[stdCode]int{
 	CodeUnauthorized.(stdCode):     http.StatusUnauthorized,
 	CodeEndpointNotFound.(stdCode): http.StatusNotFound,
 	CodeLimitTooBig.(stdCode):      http.StatusBadRequest,
+	CodeQueryTimeout.(stdCode):     http.StatusGatewayTimeout,
+	CodeNotReady.(stdCode):         http.StatusServiceUnavailable,
+}
+
+// Codes returns all predefined error codes
+func Codes() []Code {
+	return []Code{
+		CodeUnknown,
+		CodeResourceNotFound,
+		CodeBadRequest,
+		CodeUnauthorized,
+		CodeForbidden,
+		CodeEndpointNotFound,
+		CodeLimitTooBig,
+		CodeQueryTimeout,
+		CodeNotReady,
+	}
 }
 
 // Some predefined errors
@@ -83,4 +102,5 @@ var (
 	ErrResourceNotFound = New("Resource not found", CodeResourceNotFound)
 	ErrForbidden        = New("Forbidden", CodeForbidden)
 	ErrEndpointNotFound = New("Endpoint not found", CodeEndpointNotFound)
+	ErrNotReady         = New("Data is not computed yet, try again later", CodeNotReady)
 )
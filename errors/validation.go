@@ -0,0 +1,42 @@
+package errors
+
+import "strings"
+
+// FieldError describes an invalid request parameter
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ValidationError is returned when one or more request parameters are invalid
+type ValidationError struct {
+	Message string
+	Fields  []*FieldError
+	code    Code
+}
+
+func (v *ValidationError) Code() Code { return v.code }
+
+func (v *ValidationError) Error() string {
+	if len(v.Fields) == 0 {
+		return v.Message
+	}
+
+	list := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		list[i] = f.Field + ": " + f.Reason
+	}
+	return v.Message + ": " + strings.Join(list, "; ")
+}
+
+// NewValidationError returns new ValidationError. CodeBadRequest is used if code is nil.
+func NewValidationError(msg string, code Code, fields ...*FieldError) *ValidationError {
+	if code == nil {
+		code = CodeBadRequest
+	}
+	return &ValidationError{
+		Message: msg,
+		Fields:  fields,
+		code:    code,
+	}
+}
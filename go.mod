@@ -3,15 +3,20 @@ module github.com/ecadlabs/tezos-indexer-api
 go 1.12
 
 require (
+	github.com/andybalholm/brotli v1.0.0
+	github.com/golang/protobuf v1.3.5
 	github.com/gorilla/mux v1.7.3
 	github.com/gorilla/schema v1.1.0
+	github.com/jackc/pgconn v0.0.0-20190528115420-71ec1f782113
 	github.com/jackc/pgx/v4 v4.0.0-pre1
+	github.com/klauspost/compress v1.10.3
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/lib/pq v1.2.0 // indirect
 	github.com/pkg/errors v0.8.1
 	github.com/sirupsen/logrus v1.4.2
 	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4 // indirect
 	golang.org/x/text v0.3.2 // indirect
+	google.golang.org/grpc v1.27.0
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20190709130402-674ba3eaed22
 )
@@ -0,0 +1,347 @@
+// Package labels maintains human readable address labels and tags
+package labels
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"gopkg.in/yaml.v3"
+)
+
+const maxLabelLength = 256
+
+// Label describes an address
+type Label struct {
+	Address string   `json:"address" yaml:"address"`
+	Label   string   `json:"label" yaml:"label"`
+	Tags    []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Validate checks the label and normalizes tags
+func (l *Label) Validate() error {
+	var fields []*errors.FieldError
+
+	if _, err := tezos.ParseAddress(l.Address); err != nil {
+		fields = append(fields, &errors.FieldError{Field: "address", Reason: strings.TrimPrefix(err.Error(), "tezos: ")})
+	}
+
+	l.Label = strings.TrimSpace(l.Label)
+	if l.Label == "" {
+		fields = append(fields, &errors.FieldError{Field: "label", Reason: "required"})
+	} else if len(l.Label) > maxLabelLength {
+		fields = append(fields, &errors.FieldError{Field: "label", Reason: fmt.Sprintf("longer than %d bytes", maxLabelLength)})
+	}
+
+	seen := make(map[string]bool, len(l.Tags))
+	tags := make([]string, 0, len(l.Tags))
+	for _, t := range l.Tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			fields = append(fields, &errors.FieldError{Field: "tags", Reason: "empty tag"})
+			break
+		}
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	sort.Strings(tags)
+	l.Tags = tags
+
+	if len(fields) != 0 {
+		return errors.NewValidationError("Invalid label", errors.CodeBadRequest, fields...)
+	}
+	return nil
+}
+
+// Store keeps labels in memory and persists them to a YAML file. The file is a list of labels.
+type Store struct {
+	Path string
+
+	mtx     sync.RWMutex
+	labels  map[string]*Label
+	version uint64 // Hash of the label set
+}
+
+// NewStore returns a store backed by the file. A missing file is created on the first change.
+func NewStore(path string) (*Store, error) {
+	s := Store{Path: path}
+	if err := s.Load(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Load rereads the file replacing all labels. Changes can't be made in between so none of them is lost.
+func (s *Store) Load() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	buf, err := ioutil.ReadFile(s.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var list []*Label
+	if err := yaml.Unmarshal(buf, &list); err != nil {
+		return fmt.Errorf("%s: %v", s.Path, err)
+	}
+
+	labels := make(map[string]*Label, len(list))
+	for i, l := range list {
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("%s: label %d (%s): %v", s.Path, i, l.Address, err)
+		}
+		labels[l.Address] = l
+	}
+
+	s.labels, s.version = labels, labelsVersion(sortLabels(labels))
+	return nil
+}
+
+// Version is derived from labels so it's the same for the same label set regardless of how and where it was loaded
+func (s *Store) Version() uint64 {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.version
+}
+
+func sortLabels(labels map[string]*Label) []*Label {
+	list := make([]*Label, 0, len(labels))
+	for _, l := range labels {
+		list = append(list, l)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Address < list[j].Address })
+	return list
+}
+
+// labelsVersion returns FNV-1a hash of the sorted labels
+func labelsVersion(list []*Label) uint64 {
+	h := fnv.New64a()
+	json.NewEncoder(h).Encode(list)
+	return h.Sum64()
+}
+
+// Get returns the label of the address or nil
+func (s *Store) Get(address string) *Label {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.labels[address]
+}
+
+// List returns labels having the tag (all if the tag is empty) ordered by address
+func (s *Store) List(tag string) []*Label {
+	s.mtx.RLock()
+	res := make([]*Label, 0, len(s.labels))
+	for _, l := range s.labels {
+		if tag == "" || hasTag(l, tag) {
+			res = append(res, l)
+		}
+	}
+	s.mtx.RUnlock()
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Address < res[j].Address })
+	return res
+}
+
+func hasTag(l *Label, tag string) bool {
+	for _, t := range l.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Put validates and stores the label. Returns true if the address had no label.
+func (s *Store) Put(l *Label) (created bool, err error) {
+	if err := l.Validate(); err != nil {
+		return false, err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	labels := s.copyLocked()
+	_, exists := labels[l.Address]
+	labels[l.Address] = l
+
+	if err := s.commitLocked(labels); err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
+
+// Delete removes the label of the address
+func (s *Store) Delete(address string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.labels[address]; !ok {
+		return errors.ErrResourceNotFound
+	}
+
+	labels := s.copyLocked()
+	delete(labels, address)
+
+	return s.commitLocked(labels)
+}
+
+func (s *Store) copyLocked() map[string]*Label {
+	res := make(map[string]*Label, len(s.labels)+1)
+	for k, v := range s.labels {
+		res[k] = v
+	}
+	return res
+}
+
+// commitLocked writes labels to the file and makes them current. The file is replaced atomically.
+func (s *Store) commitLocked(labels map[string]*Label) error {
+	list := sortLabels(labels)
+
+	buf, err := yaml.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), "."+filepath.Base(s.Path)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return err
+	}
+
+	s.labels, s.version = labels, labelsVersion(list)
+	return nil
+}
+
+// labelRef is added next to every labelled address
+type labelRef struct {
+	Label string   `json:"label"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// LabelSuffix is appended to the key of a labelled address to get the key of its label
+const LabelSuffix = "_label"
+
+// Enrich rewrites a JSON document adding the label object under "<key>_label" after each labelled address
+// found in object members. The order of members and number literals are preserved.
+// The version of the labels used is returned so the response can be tagged with it.
+func (s *Store) Enrich(body []byte) ([]byte, uint64, error) {
+	s.mtx.RLock()
+	labels, version := s.labels, s.version
+	s.mtx.RUnlock()
+
+	if len(labels) == 0 {
+		return body, version, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.Grow(len(body))
+
+	for dec.More() {
+		if _, err := rewriteValue(dec, &buf, labels); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return buf.Bytes(), version, nil
+}
+
+// rewriteValue copies the next value and returns it if it's a string
+func rewriteValue(dec *json.Decoder, buf *bytes.Buffer, labels map[string]*Label) (*string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			buf.WriteByte('{')
+			for i := 0; dec.More(); i++ {
+				if i != 0 {
+					buf.WriteByte(',')
+				}
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				writeJSON(buf, key)
+				buf.WriteByte(':')
+
+				str, err := rewriteValue(dec, buf, labels)
+				if err != nil {
+					return nil, err
+				}
+				if str != nil {
+					if l, ok := labels[*str]; ok {
+						buf.WriteByte(',')
+						writeJSON(buf, fmt.Sprint(key)+LabelSuffix)
+						buf.WriteByte(':')
+						writeJSON(buf, &labelRef{Label: l.Label, Tags: l.Tags})
+					}
+				}
+			}
+			buf.WriteByte('}')
+
+		case '[':
+			buf.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i != 0 {
+					buf.WriteByte(',')
+				}
+				if _, err := rewriteValue(dec, buf, labels); err != nil {
+					return nil, err
+				}
+			}
+			buf.WriteByte(']')
+		}
+
+		// Closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+	case string:
+		writeJSON(buf, t)
+		return &t, nil
+
+	case json.Number:
+		buf.WriteString(string(t))
+
+	default:
+		writeJSON(buf, t)
+	}
+
+	return nil, nil
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	b, _ := json.Marshal(v)
+	buf.Write(b)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+var errUnauthorized = errors.New("Client certificate required", errors.CodeUnauthorized)
+
+// RequireIdentity middleware passes only clients authenticated by ClientCert with one of the listed identity names.
+// Nobody is allowed if the list is empty. Certificates not mapped to an identity are never allowed.
+type RequireIdentity struct {
+	Names []string
+}
+
+func (a *RequireIdentity) allowed(name string) bool {
+	if strings.HasPrefix(name, UnlistedPrefix) {
+		return false
+	}
+	for _, n := range a.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler wraps provided http.Handler with middleware
+func (a *RequireIdentity) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := utils.GetIdentity(r.Context())
+		if id == nil {
+			utils.JSONError(w, r, errUnauthorized)
+			return
+		}
+		if !a.allowed(id.Name) {
+			utils.JSONError(w, r, errors.ErrForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+// ClientCert maps a verified TLS client certificate to the client identity
+type ClientCert struct {
+	// Identities maps certificate subjects in RFC 2253 form to identity names.
+	// Unlisted certificates are named after the subject's common name prefixed with UnlistedPrefix
+	// so they never match a configured identity.
+	Identities map[string]string
+}
+
+// UnlistedPrefix starts names of identities not listed in ClientCert.Identities
+const UnlistedPrefix = "cn:"
+
+// Handler wraps provided http.Handler with middleware
+func (c *ClientCert) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cert := r.TLS.VerifiedChains[0][0]
+		id := utils.Identity{
+			Name:    UnlistedPrefix + cert.Subject.CommonName,
+			Subject: cert.Subject.String(),
+		}
+		if name, ok := c.Identities[id.Subject]; ok {
+			id.Name = name
+		}
+
+		h.ServeHTTP(w, r.WithContext(utils.WithIdentity(r.Context(), &id)))
+	})
+}
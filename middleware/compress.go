@@ -0,0 +1,336 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content codings
+const (
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+	EncodingGzip   = "gzip"
+)
+
+const defaultCompressMinSize = 1024
+
+var defaultEncodings = []string{EncodingBrotli, EncodingZstd, EncodingGzip}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type zstdCompressor struct {
+	*zstd.Encoder
+}
+
+func (z zstdCompressor) Reset(w io.Writer) { z.Encoder.Reset(w) }
+
+// CheckLevel returns an error if the level is out of the encoder range. Zero selects the encoder default level.
+func CheckLevel(encoding string, level int) error {
+	var min, max int
+	switch encoding {
+	case EncodingBrotli:
+		min, max = brotli.BestSpeed, brotli.BestCompression
+	case EncodingZstd:
+		min, max = 0, 22
+	case EncodingGzip:
+		min, max = gzip.HuffmanOnly, gzip.BestCompression
+	default:
+		return fmt.Errorf("unknown content coding %q", encoding)
+	}
+	if level < min || level > max {
+		return fmt.Errorf("%s: level %d is out of range [%d, %d]", encoding, level, min, max)
+	}
+	return nil
+}
+
+func newCompressor(encoding string, level int) (compressor, error) {
+	if err := CheckLevel(encoding, level); err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case EncodingBrotli:
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		return brotli.NewWriterLevel(nil, level), nil
+
+	case EncodingZstd:
+		opt := []zstd.EOption{
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(1 << 20),
+		}
+		if level != 0 {
+			opt = append(opt, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		enc, err := zstd.NewWriter(nil, opt...)
+		if err != nil {
+			return nil, err
+		}
+		return zstdCompressor{enc}, nil
+
+	case EncodingGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		w, err := gzip.NewWriterLevel(nil, level)
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+
+	return nil, fmt.Errorf("unknown content coding %q", encoding)
+}
+
+// Compress middleware compresses responses using the best content coding accepted by the client.
+// Bodies shorter than MinSize are sent as is unless flushed explicitly.
+type Compress struct {
+	Encodings []string       // Content codings in order of preference, defaults to br, zstd and gzip
+	Levels    map[string]int // Encoder specific compression levels keyed by content coding, optional
+	MinSize   int            // Defaults to 1024 bytes
+
+	once  sync.Once
+	err   error
+	pools map[string]*sync.Pool
+}
+
+// Init checks encoders settings. Encodings which can't be used are never negotiated, the first error is returned.
+// Called by Handler if not called explicitly.
+func (c *Compress) Init() error {
+	c.once.Do(c.init)
+	return c.err
+}
+
+func (c *Compress) init() {
+	encodings := c.Encodings
+	if len(encodings) == 0 {
+		encodings = defaultEncodings
+	}
+
+	for enc := range c.Levels {
+		if err := CheckLevel(enc, 0); err != nil && c.err == nil {
+			c.err = err
+		}
+	}
+
+	c.pools = make(map[string]*sync.Pool, len(encodings))
+	usable := make([]string, 0, len(encodings))
+	for _, enc := range encodings {
+		enc, level := enc, c.Levels[enc]
+		w, err := newCompressor(enc, level)
+		if err != nil {
+			if c.err == nil {
+				c.err = err
+			}
+			continue
+		}
+
+		pool := &sync.Pool{
+			New: func() interface{} {
+				// A nil interface must be returned rather than a typed nil
+				if w, err := newCompressor(enc, level); err == nil {
+					return w
+				}
+				return nil
+			},
+		}
+		pool.Put(w)
+		c.pools[enc] = pool
+		usable = append(usable, enc)
+	}
+	c.Encodings = usable
+}
+
+func (c *Compress) minSize() int {
+	if c.MinSize != 0 {
+		return c.MinSize
+	}
+	return defaultCompressMinSize
+}
+
+// negotiate returns the content coding with the highest quality value, ties are resolved by the server preference
+func (c *Compress) negotiate(accept string) string {
+	if accept == "" {
+		return ""
+	}
+
+	q := make(map[string]float64)
+	for _, s := range strings.Split(accept, ",") {
+		parts := strings.Split(s, ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		val := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if v, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					val = v
+				}
+			}
+		}
+		q[name] = val
+	}
+
+	var (
+		res  string
+		best float64
+	)
+	for _, enc := range c.Encodings {
+		v, ok := q[enc]
+		if !ok {
+			v, ok = q["*"]
+		}
+		if ok && v > best {
+			res, best = enc, v
+		}
+	}
+
+	return res
+}
+
+// Handler wraps provided http.Handler with middleware
+func (c *Compress) Handler(h http.Handler) http.Handler {
+	c.Init()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		enc := c.negotiate(r.Header.Get("Accept-Encoding"))
+		if enc == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+			encoding:       enc,
+			pool:           c.pools[enc],
+			minSize:        c.minSize(),
+		}
+		defer cw.close()
+
+		h.ServeHTTP(cw, r)
+	})
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	status      int
+	buf         []byte
+	compressor  compressor
+	passThrough bool // Headers have been sent, no compression
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+// start sends headers and decides whether the body will be compressed
+func (c *compressWriter) start(compress bool) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+
+	hdr := c.Header()
+	if hdr.Get("Content-Encoding") != "" || c.status < 200 || c.status == http.StatusNoContent || c.status == http.StatusNotModified {
+		compress = false
+	}
+
+	if compress {
+		if c.compressor, _ = c.pool.Get().(compressor); c.compressor == nil {
+			compress = false
+		}
+	}
+
+	if compress {
+		if hdr.Get("Content-Type") == "" && len(c.buf) != 0 {
+			hdr.Set("Content-Type", http.DetectContentType(c.buf))
+		}
+		hdr.Set("Content-Encoding", c.encoding)
+		hdr.Del("Content-Length")
+		// Compressed representation is not byte to byte identical
+		if etag := hdr.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			hdr.Set("ETag", "W/"+etag)
+		}
+		c.compressor.Reset(c.ResponseWriter)
+	} else {
+		c.passThrough = true
+	}
+
+	c.ResponseWriter.WriteHeader(c.status)
+}
+
+func (c *compressWriter) Write(data []byte) (int, error) {
+	if c.compressor != nil {
+		return c.compressor.Write(data)
+	}
+	if c.passThrough {
+		return c.ResponseWriter.Write(data)
+	}
+
+	c.buf = append(c.buf, data...)
+	if len(c.buf) < c.minSize {
+		return len(data), nil
+	}
+
+	if err := c.flushBuffer(true); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (c *compressWriter) flushBuffer(compress bool) error {
+	c.start(compress)
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := c.Write(buf)
+	return err
+}
+
+// Flush sends buffered data to the client. Streaming responses get compressed regardless of the size.
+func (c *compressWriter) Flush() {
+	if c.compressor == nil && !c.passThrough {
+		c.flushBuffer(true)
+	}
+	if c.compressor != nil {
+		c.compressor.Flush()
+	}
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *compressWriter) close() {
+	if c.compressor == nil && !c.passThrough {
+		c.flushBuffer(len(c.buf) >= c.minSize)
+	}
+	if c.compressor != nil {
+		c.compressor.Close()
+		c.compressor.Reset(nil)
+		c.pool.Put(c.compressor)
+		c.compressor = nil
+	}
+}
+
+var _ http.Flusher = &compressWriter{}
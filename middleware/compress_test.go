@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressInvalidLevel(t *testing.T) {
+	c := Compress{
+		Encodings: []string{EncodingGzip},
+		Levels:    map[string]int{EncodingGzip: 11},
+	}
+	if err := c.Init(); err == nil {
+		t.Error("error expected")
+	}
+
+	body := strings.Repeat("x", 2*defaultCompressMinSize)
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	// The unusable encoding is never negotiated
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("unexpected Content-Encoding: %s", enc)
+	}
+	if res, _ := ioutil.ReadAll(rec.Body); string(res) != body {
+		t.Error("body mismatch")
+	}
+}
+
+func TestCheckLevel(t *testing.T) {
+	tests := []struct {
+		encoding string
+		level    int
+		ok       bool
+	}{
+		{EncodingGzip, 0, true},
+		{EncodingGzip, 9, true},
+		{EncodingGzip, -2, true},
+		{EncodingGzip, 10, false},
+		{EncodingGzip, -3, false},
+		{EncodingBrotli, 11, true},
+		{EncodingBrotli, 12, false},
+		{EncodingZstd, 22, true},
+		{EncodingZstd, 23, false},
+		{"deflate", 0, false},
+	}
+
+	for _, test := range tests {
+		if err := CheckLevel(test.encoding, test.level); (err == nil) != test.ok {
+			t.Errorf("%s %d: %v", test.encoding, test.level, err)
+		}
+	}
+}
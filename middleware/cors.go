@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	defaultCORSMethods        = []string{http.MethodGet, http.MethodHead}
+	defaultCORSHeaders        = []string{"Accept", "Content-Type", "If-Modified-Since", "If-None-Match", requestIDHeader}
+	defaultCORSExposedHeaders = []string{"Deprecation", "ETag", "Link", "Sunset", requestIDHeader}
+)
+
+// CORS middleware handles cross-origin requests including preflight ones. It must wrap the router
+// because preflight OPTIONS requests don't match any route.
+// See https://fetch.spec.whatwg.org/#http-cors-protocol
+type CORS struct {
+	AllowedOrigins   []string // "*" allows any origin unless credentials are allowed
+	AllowedMethods   []string // Defaults to GET and HEAD
+	AllowedHeaders   []string // Request headers allowed in addition to CORS-safelisted ones, "*" allows any
+	ExposedHeaders   []string // Response headers exposed to scripts besides CORS-safelisted ones
+	AllowCredentials bool
+	MaxAge           time.Duration // Preflight response cache lifetime, optional
+}
+
+// anyOrigin returns true if the wildcard is in effect. It's ignored if credentials are allowed
+// so an arbitrary origin is never given access to credentialed responses.
+func (c *CORS) anyOrigin() bool {
+	if c.AllowCredentials {
+		return false
+	}
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORS) originAllowed(origin string) bool {
+	if c.anyOrigin() {
+		return true
+	}
+	for _, o := range c.AllowedOrigins {
+		if o != "*" && strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORS) methods() []string {
+	if len(c.AllowedMethods) != 0 {
+		return c.AllowedMethods
+	}
+	return defaultCORSMethods
+}
+
+func (c *CORS) headers() []string {
+	if len(c.AllowedHeaders) != 0 {
+		return c.AllowedHeaders
+	}
+	return defaultCORSHeaders
+}
+
+func (c *CORS) exposedHeaders() []string {
+	if c.ExposedHeaders != nil {
+		return c.ExposedHeaders
+	}
+	return defaultCORSExposedHeaders
+}
+
+func (c *CORS) methodAllowed(method string) bool {
+	for _, m := range c.methods() {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORS) headersAllowed(headers string) bool {
+	allowed := c.headers()
+	for _, h := range strings.Split(headers, ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		var ok bool
+		for _, a := range allowed {
+			if a == "*" || strings.EqualFold(a, h) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler wraps provided http.Handler with middleware
+func (c *CORS) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hdr := w.Header()
+		hdr.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" || !c.originAllowed(origin) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		reqMethod := r.Header.Get("Access-Control-Request-Method")
+		preflight := r.Method == http.MethodOptions && reqMethod != ""
+
+		if preflight {
+			hdr.Add("Vary", "Access-Control-Request-Method")
+			hdr.Add("Vary", "Access-Control-Request-Headers")
+
+			reqHeaders := r.Header.Get("Access-Control-Request-Headers")
+			if !c.methodAllowed(reqMethod) || !c.headersAllowed(reqHeaders) {
+				// Leave CORS headers out so the browser rejects the request
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		// Only explicitly listed origins are echoed back
+		if c.anyOrigin() {
+			hdr.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			hdr.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if c.AllowCredentials {
+			hdr.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exp := c.exposedHeaders(); len(exp) != 0 {
+				hdr.Set("Access-Control-Expose-Headers", strings.Join(exp, ", "))
+			}
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		hdr.Set("Access-Control-Allow-Methods", strings.Join(c.methods(), ", "))
+		if reqHeaders := r.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+			hdr.Set("Access-Control-Allow-Headers", reqHeaders)
+		}
+		if c.MaxAge > 0 {
+			hdr.Set("Access-Control-Max-Age", strconv.FormatInt(int64(c.MaxAge/time.Second), 10))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// Deprecation middleware marks responses with Deprecation and Sunset headers
+// See https://tools.ietf.org/html/draft-dalal-deprecation-header and RFC 8594
+type Deprecation struct {
+	Date      time.Time                    // Deprecation date, "true" is sent if zero
+	Sunset    time.Time                    // Date after which the resource is expected to become unresponsive, optional
+	Successor func(r *http.Request) string // Returns URL of the successor resource, optional
+}
+
+// Handler wraps provided http.Handler with middleware
+func (d *Deprecation) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hdr := w.Header()
+
+		if d.Date.IsZero() {
+			hdr.Set("Deprecation", "true")
+		} else {
+			hdr.Set("Deprecation", d.Date.UTC().Format(http.TimeFormat))
+		}
+
+		if !d.Sunset.IsZero() {
+			hdr.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		if d.Successor != nil {
+			if u := d.Successor(r); u != "" {
+				hdr.Add("Link", "<"+u+">; rel=\"successor-version\"")
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ecadlabs/tezos-indexer-api/utils"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -38,6 +39,14 @@ func (l *Logging) Handler(h http.Handler) http.Handler {
 			"path":       r.URL.Path,
 		}
 
+		if id := utils.RequestID(r.Context()); id != "" {
+			fields["request_id"] = id
+		}
+
+		if id := utils.GetIdentity(r.Context()); id != nil {
+			fields["identity"] = id.Name
+		}
+
 		l.log().WithFields(fields).Println(r.Method + " " + r.URL.Path)
 	})
 }
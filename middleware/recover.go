@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -34,9 +36,12 @@ func (r *Recover) Handler(h http.Handler) http.Handler {
 				"method": req.Method,
 				"path":   req.URL.Path,
 			}
+			if id := utils.RequestID(req.Context()); id != "" {
+				fields["request_id"] = id
+			}
 			r.log().WithFields(fields).Println(string(stack))
 
-			http.Error(w, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			utils.JSONError(w, req, errors.New(fmt.Sprintf("%v", err), errors.CodeUnknown))
 		}()
 
 		h.ServeHTTP(w, req)
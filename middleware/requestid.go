@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID assigns an ID to each request. Valid IDs provided by the client (e.g. a proxy) are preserved.
+type RequestID struct{}
+
+func newRequestID() string {
+	var buf [16]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// Handler wraps provided http.Handler with middleware
+func (RequestID) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !requestIDRegexp.MatchString(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		h.ServeHTTP(w, r.WithContext(utils.WithRequestID(r.Context(), id)))
+	})
+}
@@ -53,6 +53,14 @@ func (rw *responseWriter) Write(data []byte) (int, error) {
 	return rw.ResponseWriter.Write(data)
 }
 
+// Flush implements http.Flusher if the underlying writer does
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 var _ http.ResponseWriter = &responseWriter{}
 var _ http.ResponseWriter = &responseWriterHijacker{}
 var _ http.Hijacker = &responseWriterHijacker{}
+var _ http.Flusher = &responseWriter{}
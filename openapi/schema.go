@@ -0,0 +1,173 @@
+package openapi
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+// Generator builds schemas from Go types. Named struct types are stored as components and referenced.
+type Generator struct {
+	Components *Components
+}
+
+// NewGenerator returns new Generator storing named schemas into c
+func NewGenerator(c *Components) *Generator {
+	if c.Schemas == nil {
+		c.Schemas = make(map[string]*Schema)
+	}
+	return &Generator{Components: c}
+}
+
+// Schema returns a schema of v's type
+func (g *Generator) Schema(v interface{}) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+func (g *Generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	// Arbitrary precision integers are marshalled as JSON numbers
+	if t == bigIntType {
+		return &Schema{Type: "integer"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := g.Components.Schemas[name]; !ok {
+			// Reserve the name first to handle recursive types
+			g.Components.Schemas[name] = &Schema{}
+			*g.Components.Schemas[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// Fields of embedded unexported structs are promoted like in encoding/json
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		name, opts := parseTag(f.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			emb := g.structSchema(indirect(f.Type))
+			for k, v := range emb.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, emb.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(f.Type)
+		if d := f.Tag.Get("description"); d != "" && fs.Ref == "" {
+			fs.Description = d
+		}
+		s.Properties[name] = fs
+
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s
+}
+
+// Parameters returns query parameters described by gorilla/schema tags of v's type
+func (g *Generator) Parameters(v interface{}) []*Parameter {
+	t := indirect(reflect.TypeOf(v))
+	var res []*Parameter
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opt := parseTag(f.Tag.Get("schema"))
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		res = append(res, &Parameter{
+			Name:        name,
+			In:          "query",
+			Description: f.Tag.Get("description"),
+			Required:    opt == "required",
+			Schema:      g.schema(f.Type),
+		})
+	}
+
+	return res
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func parseTag(tag string) (string, string) {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
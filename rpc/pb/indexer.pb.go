@@ -0,0 +1,1139 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: indexer.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetBalanceUpdatesRequest struct {
+	Address              string               `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Start                *timestamp.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End                  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Limit                int32                `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetBalanceUpdatesRequest) Reset()         { *m = GetBalanceUpdatesRequest{} }
+func (m *GetBalanceUpdatesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceUpdatesRequest) ProtoMessage()    {}
+func (*GetBalanceUpdatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{0}
+}
+
+func (m *GetBalanceUpdatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceUpdatesRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceUpdatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceUpdatesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceUpdatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceUpdatesRequest.Merge(m, src)
+}
+func (m *GetBalanceUpdatesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceUpdatesRequest.Size(m)
+}
+func (m *GetBalanceUpdatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceUpdatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceUpdatesRequest proto.InternalMessageInfo
+
+func (m *GetBalanceUpdatesRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetBalanceUpdatesRequest) GetStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *GetBalanceUpdatesRequest) GetEnd() *timestamp.Timestamp {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *GetBalanceUpdatesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type BalanceUpdate struct {
+	Level                int64                `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	BlockHash            string               `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Diff                 int64                `protobuf:"varint,4,opt,name=diff,proto3" json:"diff,omitempty"`
+	Value                int64                `protobuf:"varint,5,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BalanceUpdate) Reset()         { *m = BalanceUpdate{} }
+func (m *BalanceUpdate) String() string { return proto.CompactTextString(m) }
+func (*BalanceUpdate) ProtoMessage()    {}
+func (*BalanceUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{1}
+}
+
+func (m *BalanceUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceUpdate.Unmarshal(m, b)
+}
+func (m *BalanceUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceUpdate.Marshal(b, m, deterministic)
+}
+func (m *BalanceUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceUpdate.Merge(m, src)
+}
+func (m *BalanceUpdate) XXX_Size() int {
+	return xxx_messageInfo_BalanceUpdate.Size(m)
+}
+func (m *BalanceUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceUpdate proto.InternalMessageInfo
+
+func (m *BalanceUpdate) GetLevel() int64 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *BalanceUpdate) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *BalanceUpdate) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *BalanceUpdate) GetDiff() int64 {
+	if m != nil {
+		return m.Diff
+	}
+	return 0
+}
+
+func (m *BalanceUpdate) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type GetOperationsRequest struct {
+	Address              string               `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Start                *timestamp.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End                  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Limit                int32                `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetOperationsRequest) Reset()         { *m = GetOperationsRequest{} }
+func (m *GetOperationsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetOperationsRequest) ProtoMessage()    {}
+func (*GetOperationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{2}
+}
+
+func (m *GetOperationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOperationsRequest.Unmarshal(m, b)
+}
+func (m *GetOperationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOperationsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetOperationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOperationsRequest.Merge(m, src)
+}
+func (m *GetOperationsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetOperationsRequest.Size(m)
+}
+func (m *GetOperationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOperationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOperationsRequest proto.InternalMessageInfo
+
+func (m *GetOperationsRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *GetOperationsRequest) GetStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *GetOperationsRequest) GetEnd() *timestamp.Timestamp {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *GetOperationsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type Operation struct {
+	Hash      string               `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Id        int32                `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Level     int64                `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	BlockHash string               `protobuf:"bytes,4,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    string               `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//	*Operation_Transaction
+	//	*Operation_Origination
+	//	*Operation_Delegation
+	Content              isOperation_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Operation) Reset()         { *m = Operation{} }
+func (m *Operation) String() string { return proto.CompactTextString(m) }
+func (*Operation) ProtoMessage()    {}
+func (*Operation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{3}
+}
+
+func (m *Operation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Operation.Unmarshal(m, b)
+}
+func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Operation.Marshal(b, m, deterministic)
+}
+func (m *Operation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operation.Merge(m, src)
+}
+func (m *Operation) XXX_Size() int {
+	return xxx_messageInfo_Operation.Size(m)
+}
+func (m *Operation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Operation proto.InternalMessageInfo
+
+func (m *Operation) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Operation) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Operation) GetLevel() int64 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *Operation) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *Operation) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *Operation) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+type isOperation_Content interface {
+	isOperation_Content()
+}
+
+type Operation_Transaction struct {
+	Transaction *Transaction `protobuf:"bytes,7,opt,name=transaction,proto3,oneof"`
+}
+
+type Operation_Origination struct {
+	Origination *Origination `protobuf:"bytes,8,opt,name=origination,proto3,oneof"`
+}
+
+type Operation_Delegation struct {
+	Delegation *Delegation `protobuf:"bytes,9,opt,name=delegation,proto3,oneof"`
+}
+
+func (*Operation_Transaction) isOperation_Content() {}
+
+func (*Operation_Origination) isOperation_Content() {}
+
+func (*Operation_Delegation) isOperation_Content() {}
+
+func (m *Operation) GetContent() isOperation_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *Operation) GetTransaction() *Transaction {
+	if x, ok := m.GetContent().(*Operation_Transaction); ok {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (m *Operation) GetOrigination() *Origination {
+	if x, ok := m.GetContent().(*Operation_Origination); ok {
+		return x.Origination
+	}
+	return nil
+}
+
+func (m *Operation) GetDelegation() *Delegation {
+	if x, ok := m.GetContent().(*Operation_Delegation); ok {
+		return x.Delegation
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Operation) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Operation_Transaction)(nil),
+		(*Operation_Origination)(nil),
+		(*Operation_Delegation)(nil),
+	}
+}
+
+type Transaction struct {
+	Destination          string   `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	Amount               int64    `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Fee                  int64    `protobuf:"varint,3,opt,name=fee,proto3" json:"fee,omitempty"`
+	Parameters           string   `protobuf:"bytes,4,opt,name=parameters,proto3" json:"parameters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{4}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *Transaction) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *Transaction) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *Transaction) GetParameters() string {
+	if m != nil {
+		return m.Parameters
+	}
+	return ""
+}
+
+type Origination struct {
+	Contract             string   `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Origination) Reset()         { *m = Origination{} }
+func (m *Origination) String() string { return proto.CompactTextString(m) }
+func (*Origination) ProtoMessage()    {}
+func (*Origination) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{5}
+}
+
+func (m *Origination) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Origination.Unmarshal(m, b)
+}
+func (m *Origination) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Origination.Marshal(b, m, deterministic)
+}
+func (m *Origination) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Origination.Merge(m, src)
+}
+func (m *Origination) XXX_Size() int {
+	return xxx_messageInfo_Origination.Size(m)
+}
+func (m *Origination) XXX_DiscardUnknown() {
+	xxx_messageInfo_Origination.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Origination proto.InternalMessageInfo
+
+func (m *Origination) GetContract() string {
+	if m != nil {
+		return m.Contract
+	}
+	return ""
+}
+
+type Delegation struct {
+	Delegate             string   `protobuf:"bytes,1,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Delegation) Reset()         { *m = Delegation{} }
+func (m *Delegation) String() string { return proto.CompactTextString(m) }
+func (*Delegation) ProtoMessage()    {}
+func (*Delegation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{6}
+}
+
+func (m *Delegation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Delegation.Unmarshal(m, b)
+}
+func (m *Delegation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Delegation.Marshal(b, m, deterministic)
+}
+func (m *Delegation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Delegation.Merge(m, src)
+}
+func (m *Delegation) XXX_Size() int {
+	return xxx_messageInfo_Delegation.Size(m)
+}
+func (m *Delegation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Delegation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Delegation proto.InternalMessageInfo
+
+func (m *Delegation) GetDelegate() string {
+	if m != nil {
+		return m.Delegate
+	}
+	return ""
+}
+
+type GetHeadRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetHeadRequest) Reset()         { *m = GetHeadRequest{} }
+func (m *GetHeadRequest) String() string { return proto.CompactTextString(m) }
+func (*GetHeadRequest) ProtoMessage()    {}
+func (*GetHeadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{7}
+}
+
+func (m *GetHeadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHeadRequest.Unmarshal(m, b)
+}
+func (m *GetHeadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHeadRequest.Marshal(b, m, deterministic)
+}
+func (m *GetHeadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHeadRequest.Merge(m, src)
+}
+func (m *GetHeadRequest) XXX_Size() int {
+	return xxx_messageInfo_GetHeadRequest.Size(m)
+}
+func (m *GetHeadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHeadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHeadRequest proto.InternalMessageInfo
+
+type GetBlockRequest struct {
+	Level                int64    `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBlockRequest) Reset()         { *m = GetBlockRequest{} }
+func (m *GetBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockRequest) ProtoMessage()    {}
+func (*GetBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{8}
+}
+
+func (m *GetBlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlockRequest.Unmarshal(m, b)
+}
+func (m *GetBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlockRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlockRequest.Merge(m, src)
+}
+func (m *GetBlockRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBlockRequest.Size(m)
+}
+func (m *GetBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlockRequest proto.InternalMessageInfo
+
+func (m *GetBlockRequest) GetLevel() int64 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+type Block struct {
+	Hash                 string               `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Level                int64                `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	Predecessor          string               `protobuf:"bytes,3,opt,name=predecessor,proto3" json:"predecessor,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{9}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Block) GetLevel() int64 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *Block) GetPredecessor() string {
+	if m != nil {
+		return m.Predecessor
+	}
+	return ""
+}
+
+func (m *Block) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type ListForksRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListForksRequest) Reset()         { *m = ListForksRequest{} }
+func (m *ListForksRequest) String() string { return proto.CompactTextString(m) }
+func (*ListForksRequest) ProtoMessage()    {}
+func (*ListForksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{10}
+}
+
+func (m *ListForksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListForksRequest.Unmarshal(m, b)
+}
+func (m *ListForksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListForksRequest.Marshal(b, m, deterministic)
+}
+func (m *ListForksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListForksRequest.Merge(m, src)
+}
+func (m *ListForksRequest) XXX_Size() int {
+	return xxx_messageInfo_ListForksRequest.Size(m)
+}
+func (m *ListForksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListForksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListForksRequest proto.InternalMessageInfo
+
+type Fork struct {
+	AncestorLevel        int64    `protobuf:"varint,1,opt,name=ancestor_level,json=ancestorLevel,proto3" json:"ancestor_level,omitempty"`
+	AncestorHash         string   `protobuf:"bytes,2,opt,name=ancestor_hash,json=ancestorHash,proto3" json:"ancestor_hash,omitempty"`
+	FirstLevel           int64    `protobuf:"varint,3,opt,name=first_level,json=firstLevel,proto3" json:"first_level,omitempty"`
+	LastLevel            int64    `protobuf:"varint,4,opt,name=last_level,json=lastLevel,proto3" json:"last_level,omitempty"`
+	Depth                int64    `protobuf:"varint,5,opt,name=depth,proto3" json:"depth,omitempty"`
+	Blocks               []*Block `protobuf:"bytes,6,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Fork) Reset()         { *m = Fork{} }
+func (m *Fork) String() string { return proto.CompactTextString(m) }
+func (*Fork) ProtoMessage()    {}
+func (*Fork) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b06a290ab031ed6, []int{11}
+}
+
+func (m *Fork) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Fork.Unmarshal(m, b)
+}
+func (m *Fork) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Fork.Marshal(b, m, deterministic)
+}
+func (m *Fork) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Fork.Merge(m, src)
+}
+func (m *Fork) XXX_Size() int {
+	return xxx_messageInfo_Fork.Size(m)
+}
+func (m *Fork) XXX_DiscardUnknown() {
+	xxx_messageInfo_Fork.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Fork proto.InternalMessageInfo
+
+func (m *Fork) GetAncestorLevel() int64 {
+	if m != nil {
+		return m.AncestorLevel
+	}
+	return 0
+}
+
+func (m *Fork) GetAncestorHash() string {
+	if m != nil {
+		return m.AncestorHash
+	}
+	return ""
+}
+
+func (m *Fork) GetFirstLevel() int64 {
+	if m != nil {
+		return m.FirstLevel
+	}
+	return 0
+}
+
+func (m *Fork) GetLastLevel() int64 {
+	if m != nil {
+		return m.LastLevel
+	}
+	return 0
+}
+
+func (m *Fork) GetDepth() int64 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *Fork) GetBlocks() []*Block {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*GetBalanceUpdatesRequest)(nil), "tezos.indexer.v1.GetBalanceUpdatesRequest")
+	proto.RegisterType((*BalanceUpdate)(nil), "tezos.indexer.v1.BalanceUpdate")
+	proto.RegisterType((*GetOperationsRequest)(nil), "tezos.indexer.v1.GetOperationsRequest")
+	proto.RegisterType((*Operation)(nil), "tezos.indexer.v1.Operation")
+	proto.RegisterType((*Transaction)(nil), "tezos.indexer.v1.Transaction")
+	proto.RegisterType((*Origination)(nil), "tezos.indexer.v1.Origination")
+	proto.RegisterType((*Delegation)(nil), "tezos.indexer.v1.Delegation")
+	proto.RegisterType((*GetHeadRequest)(nil), "tezos.indexer.v1.GetHeadRequest")
+	proto.RegisterType((*GetBlockRequest)(nil), "tezos.indexer.v1.GetBlockRequest")
+	proto.RegisterType((*Block)(nil), "tezos.indexer.v1.Block")
+	proto.RegisterType((*ListForksRequest)(nil), "tezos.indexer.v1.ListForksRequest")
+	proto.RegisterType((*Fork)(nil), "tezos.indexer.v1.Fork")
+}
+
+func init() {
+	proto.RegisterFile("indexer.proto", fileDescriptor_2b06a290ab031ed6)
+}
+
+var fileDescriptor_2b06a290ab031ed6 = []byte{
+	// 789 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x55, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x36, 0x45, 0x51, 0x32, 0x87, 0x95, 0xab, 0x2e, 0x0c, 0x97, 0x50, 0xeb, 0x5a, 0x65, 0xd1,
+	0x56, 0x2d, 0x62, 0x52, 0x76, 0x2e, 0x01, 0x02, 0x04, 0x88, 0x61, 0xd8, 0x32, 0x60, 0xc0, 0x00,
+	0xe1, 0xe4, 0x90, 0x8b, 0xb1, 0x24, 0x47, 0x12, 0x61, 0x8a, 0x64, 0x76, 0x57, 0x46, 0x92, 0x17,
+	0xc8, 0x29, 0xaf, 0x90, 0x63, 0x10, 0xe4, 0x8d, 0xf2, 0x36, 0x01, 0x97, 0x3f, 0xa2, 0x2d, 0x29,
+	0x89, 0x6f, 0xb9, 0x71, 0x66, 0xbf, 0xfd, 0xf6, 0x9b, 0xd9, 0x6f, 0x96, 0xd0, 0x09, 0xe3, 0x00,
+	0x5f, 0x21, 0xb3, 0x53, 0x96, 0x88, 0x84, 0x74, 0x05, 0xbe, 0x49, 0xb8, 0x5d, 0x26, 0x6f, 0x0e,
+	0x7a, 0x7b, 0x93, 0x24, 0x99, 0x44, 0xe8, 0xc8, 0x75, 0x6f, 0x3e, 0x76, 0x44, 0x38, 0x43, 0x2e,
+	0xe8, 0x2c, 0xcd, 0xb7, 0x58, 0x9f, 0x14, 0x30, 0x4f, 0x51, 0x1c, 0xd1, 0x88, 0xc6, 0x3e, 0x3e,
+	0x4b, 0x03, 0x2a, 0x90, 0xbb, 0xf8, 0x72, 0x8e, 0x5c, 0x10, 0x13, 0xda, 0x34, 0x08, 0x18, 0x72,
+	0x6e, 0x2a, 0x7d, 0x65, 0xa0, 0xbb, 0x65, 0x48, 0x86, 0xa0, 0x71, 0x41, 0x99, 0x30, 0x1b, 0x7d,
+	0x65, 0x60, 0x1c, 0xf6, 0xec, 0xfc, 0x1c, 0xbb, 0x3c, 0xc7, 0xbe, 0x2c, 0xcf, 0x71, 0x73, 0x20,
+	0x79, 0x00, 0x2a, 0xc6, 0x81, 0xa9, 0x7e, 0x13, 0x9f, 0xc1, 0xc8, 0x36, 0x68, 0x51, 0x38, 0x0b,
+	0x85, 0xd9, 0xec, 0x2b, 0x03, 0xcd, 0xcd, 0x03, 0xeb, 0xa3, 0x02, 0x9d, 0x5b, 0x4a, 0x25, 0x0e,
+	0x6f, 0x30, 0x92, 0xfa, 0x54, 0x37, 0x0f, 0xc8, 0x2e, 0x80, 0x17, 0x25, 0xfe, 0xf5, 0xd5, 0x94,
+	0xf2, 0xa9, 0x94, 0xa8, 0xbb, 0xba, 0xcc, 0x8c, 0x28, 0x9f, 0x92, 0x47, 0xa0, 0x57, 0x6d, 0xf8,
+	0x0e, 0x41, 0x0b, 0x30, 0x21, 0xd0, 0x0c, 0xc2, 0xf1, 0x58, 0xaa, 0x52, 0x5d, 0xf9, 0x9d, 0x49,
+	0xb8, 0xa1, 0xd1, 0x1c, 0x4d, 0x2d, 0x97, 0x20, 0x03, 0xeb, 0x83, 0x02, 0xdb, 0xa7, 0x28, 0x2e,
+	0x52, 0x64, 0x54, 0x84, 0x49, 0xfc, 0xc3, 0xf6, 0xf4, 0xad, 0x0a, 0x7a, 0xa5, 0x32, 0x2b, 0x50,
+	0xf6, 0x2c, 0x97, 0x26, 0xbf, 0xc9, 0x16, 0x34, 0xc2, 0x40, 0x8a, 0xd2, 0xdc, 0x46, 0x18, 0x2c,
+	0x7a, 0xae, 0xae, 0xef, 0x79, 0xf3, 0xab, 0x3d, 0xd7, 0xee, 0xd3, 0xf3, 0x1d, 0x68, 0xf1, 0x64,
+	0xce, 0x7c, 0x34, 0x5b, 0x92, 0xb4, 0x88, 0xc8, 0x53, 0x30, 0x04, 0xa3, 0x31, 0xa7, 0x7e, 0xa6,
+	0xdc, 0x6c, 0x4b, 0xce, 0x5d, 0xfb, 0xee, 0x08, 0xd8, 0x97, 0x0b, 0xd0, 0x68, 0xc3, 0xad, 0xef,
+	0xc9, 0x28, 0x12, 0x16, 0x4e, 0xc2, 0x58, 0x16, 0x6f, 0x6e, 0xae, 0xa3, 0xb8, 0x58, 0x80, 0x32,
+	0x8a, 0xda, 0x1e, 0xf2, 0x04, 0x20, 0xc0, 0x08, 0x27, 0x39, 0x83, 0x2e, 0x19, 0x7e, 0x5f, 0x66,
+	0x38, 0xae, 0x30, 0xa3, 0x0d, 0xb7, 0xb6, 0xe3, 0x48, 0x87, 0xb6, 0x9f, 0xc4, 0x02, 0x63, 0x61,
+	0xbd, 0x06, 0xa3, 0xa6, 0x95, 0xf4, 0xc1, 0x08, 0x90, 0x8b, 0x52, 0x5c, 0x7e, 0x23, 0xf5, 0x54,
+	0xd6, 0x19, 0x3a, 0x4b, 0xe6, 0x71, 0xee, 0x18, 0xd5, 0x2d, 0x22, 0xd2, 0x05, 0x75, 0x8c, 0x58,
+	0x5c, 0x4f, 0xf6, 0x49, 0xfe, 0x00, 0x48, 0x29, 0xa3, 0x33, 0x14, 0xc8, 0x78, 0x71, 0x39, 0xb5,
+	0x8c, 0xf5, 0x1f, 0x18, 0xb5, 0x1a, 0x49, 0x0f, 0x36, 0x33, 0x51, 0x8c, 0xfa, 0xa2, 0x38, 0xb7,
+	0x8a, 0xad, 0x01, 0xc0, 0xa2, 0x98, 0x0c, 0x59, 0x14, 0x83, 0x25, 0xb2, 0x8c, 0xad, 0x2e, 0x6c,
+	0x9d, 0xa2, 0x18, 0x21, 0x0d, 0x0a, 0xef, 0x5b, 0xff, 0xc2, 0xcf, 0xd9, 0x5b, 0x93, 0x99, 0xa2,
+	0x48, 0xad, 0x1e, 0x60, 0xeb, 0x9d, 0x02, 0x9a, 0x84, 0xad, 0x34, 0x64, 0xb5, 0xa7, 0x51, 0x37,
+	0x60, 0x1f, 0x8c, 0x94, 0x61, 0x80, 0x3e, 0x72, 0x9e, 0x30, 0x59, 0xbd, 0xee, 0xd6, 0x53, 0xb7,
+	0x3d, 0xd8, 0xbc, 0x87, 0x07, 0x2d, 0x02, 0xdd, 0xf3, 0x90, 0x8b, 0x93, 0x84, 0x5d, 0x97, 0x83,
+	0x6c, 0x7d, 0x56, 0xa0, 0x99, 0x25, 0xc8, 0xdf, 0xb0, 0x95, 0xbd, 0x48, 0x5c, 0x24, 0xec, 0xaa,
+	0x5e, 0x4b, 0xa7, 0xcc, 0x9e, 0x4b, 0x7d, 0x7f, 0x41, 0x95, 0xa8, 0xbf, 0x4b, 0x3f, 0x95, 0x49,
+	0x39, 0x26, 0x7b, 0x60, 0x8c, 0x43, 0xc6, 0xc5, 0x55, 0x7d, 0xc2, 0x40, 0xa6, 0xce, 0xcb, 0x31,
+	0x8b, 0x68, 0xb5, 0x9e, 0xbf, 0x43, 0x7a, 0x44, 0xcb, 0xe5, 0x6d, 0xd0, 0x02, 0x4c, 0xc5, 0xb4,
+	0x7c, 0x8c, 0x64, 0x40, 0x1c, 0x68, 0xc9, 0x49, 0xe4, 0x66, 0xab, 0xaf, 0x0e, 0x8c, 0xc3, 0x5f,
+	0x97, 0x0d, 0x9a, 0x5f, 0x4a, 0x01, 0x3b, 0x7c, 0xaf, 0x42, 0xfb, 0x2c, 0x5f, 0x24, 0x1e, 0xfc,
+	0xb2, 0xf4, 0x83, 0x20, 0xff, 0x2f, 0x33, 0xac, 0xfb, 0x8b, 0xf4, 0xf6, 0x56, 0x9c, 0x56, 0x07,
+	0x0e, 0x15, 0xf2, 0x1c, 0x3a, 0xb7, 0x1e, 0x4b, 0xf2, 0xcf, 0x4a, 0xfe, 0xa5, 0xd7, 0xb4, 0xf7,
+	0xdb, 0x8a, 0x61, 0x2d, 0x41, 0x43, 0x85, 0x1c, 0x43, 0xbb, 0xb0, 0x20, 0xe9, 0xaf, 0x64, 0xac,
+	0xb9, 0xb3, 0xb7, 0xae, 0x2b, 0xe4, 0x04, 0x36, 0x4b, 0xdb, 0x92, 0x3f, 0x57, 0x17, 0x5e, 0xb3,
+	0xf4, 0x7a, 0x9e, 0x33, 0xd0, 0x2b, 0x17, 0x11, 0x6b, 0x19, 0x75, 0xd7, 0x62, 0xbd, 0x9d, 0x65,
+	0x4c, 0xb6, 0x3e, 0x54, 0x8e, 0x0e, 0x5e, 0x38, 0x93, 0x50, 0x4c, 0xe7, 0x9e, 0xed, 0x27, 0x33,
+	0x07, 0x7d, 0x1a, 0x44, 0xd4, 0xe3, 0x8e, 0x84, 0xef, 0x17, 0xf0, 0x7d, 0x9a, 0x86, 0x0e, 0x4b,
+	0x7d, 0x27, 0xf5, 0x1e, 0xa7, 0x9e, 0xd7, 0x92, 0x16, 0x7f, 0xf8, 0x65, 0x00, 0x09, 0x0a, 0xc1,
+	0x18, 0x34, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// IndexerClient is the client API for Indexer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type IndexerClient interface {
+	// GetBalanceUpdates streams balance updates of the contract in descending level order
+	GetBalanceUpdates(ctx context.Context, in *GetBalanceUpdatesRequest, opts ...grpc.CallOption) (Indexer_GetBalanceUpdatesClient, error)
+	// GetOperations streams transactions, originations and delegations involving the account in descending level order
+	GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (Indexer_GetOperationsClient, error)
+	// GetHead returns the chain head
+	GetHead(ctx context.Context, in *GetHeadRequest, opts ...grpc.CallOption) (*Block, error)
+	// GetBlock returns the canonical block at the given level
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// ListForks streams chain branches which don't lead to the current head
+	ListForks(ctx context.Context, in *ListForksRequest, opts ...grpc.CallOption) (Indexer_ListForksClient, error)
+}
+
+type indexerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIndexerClient(cc grpc.ClientConnInterface) IndexerClient {
+	return &indexerClient{cc}
+}
+
+func (c *indexerClient) GetBalanceUpdates(ctx context.Context, in *GetBalanceUpdatesRequest, opts ...grpc.CallOption) (Indexer_GetBalanceUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Indexer_serviceDesc.Streams[0], "/tezos.indexer.v1.Indexer/GetBalanceUpdates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &indexerGetBalanceUpdatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Indexer_GetBalanceUpdatesClient interface {
+	Recv() (*BalanceUpdate, error)
+	grpc.ClientStream
+}
+
+type indexerGetBalanceUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *indexerGetBalanceUpdatesClient) Recv() (*BalanceUpdate, error) {
+	m := new(BalanceUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *indexerClient) GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (Indexer_GetOperationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Indexer_serviceDesc.Streams[1], "/tezos.indexer.v1.Indexer/GetOperations", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &indexerGetOperationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Indexer_GetOperationsClient interface {
+	Recv() (*Operation, error)
+	grpc.ClientStream
+}
+
+type indexerGetOperationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *indexerGetOperationsClient) Recv() (*Operation, error) {
+	m := new(Operation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *indexerClient) GetHead(ctx context.Context, in *GetHeadRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/tezos.indexer.v1.Indexer/GetHead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexerClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/tezos.indexer.v1.Indexer/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexerClient) ListForks(ctx context.Context, in *ListForksRequest, opts ...grpc.CallOption) (Indexer_ListForksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Indexer_serviceDesc.Streams[2], "/tezos.indexer.v1.Indexer/ListForks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &indexerListForksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Indexer_ListForksClient interface {
+	Recv() (*Fork, error)
+	grpc.ClientStream
+}
+
+type indexerListForksClient struct {
+	grpc.ClientStream
+}
+
+func (x *indexerListForksClient) Recv() (*Fork, error) {
+	m := new(Fork)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// IndexerServer is the server API for Indexer service.
+type IndexerServer interface {
+	// GetBalanceUpdates streams balance updates of the contract in descending level order
+	GetBalanceUpdates(*GetBalanceUpdatesRequest, Indexer_GetBalanceUpdatesServer) error
+	// GetOperations streams transactions, originations and delegations involving the account in descending level order
+	GetOperations(*GetOperationsRequest, Indexer_GetOperationsServer) error
+	// GetHead returns the chain head
+	GetHead(context.Context, *GetHeadRequest) (*Block, error)
+	// GetBlock returns the canonical block at the given level
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	// ListForks streams chain branches which don't lead to the current head
+	ListForks(*ListForksRequest, Indexer_ListForksServer) error
+}
+
+// UnimplementedIndexerServer can be embedded to have forward compatible implementations.
+type UnimplementedIndexerServer struct {
+}
+
+func (*UnimplementedIndexerServer) GetBalanceUpdates(req *GetBalanceUpdatesRequest, srv Indexer_GetBalanceUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetBalanceUpdates not implemented")
+}
+func (*UnimplementedIndexerServer) GetOperations(req *GetOperationsRequest, srv Indexer_GetOperationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetOperations not implemented")
+}
+func (*UnimplementedIndexerServer) GetHead(ctx context.Context, req *GetHeadRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHead not implemented")
+}
+func (*UnimplementedIndexerServer) GetBlock(ctx context.Context, req *GetBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (*UnimplementedIndexerServer) ListForks(req *ListForksRequest, srv Indexer_ListForksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListForks not implemented")
+}
+
+func RegisterIndexerServer(s *grpc.Server, srv IndexerServer) {
+	s.RegisterService(&_Indexer_serviceDesc, srv)
+}
+
+func _Indexer_GetBalanceUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetBalanceUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IndexerServer).GetBalanceUpdates(m, &indexerGetBalanceUpdatesServer{stream})
+}
+
+type Indexer_GetBalanceUpdatesServer interface {
+	Send(*BalanceUpdate) error
+	grpc.ServerStream
+}
+
+type indexerGetBalanceUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *indexerGetBalanceUpdatesServer) Send(m *BalanceUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Indexer_GetOperations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetOperationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IndexerServer).GetOperations(m, &indexerGetOperationsServer{stream})
+}
+
+type Indexer_GetOperationsServer interface {
+	Send(*Operation) error
+	grpc.ServerStream
+}
+
+type indexerGetOperationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *indexerGetOperationsServer) Send(m *Operation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Indexer_GetHead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerServer).GetHead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tezos.indexer.v1.Indexer/GetHead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerServer).GetHead(ctx, req.(*GetHeadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indexer_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tezos.indexer.v1.Indexer/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indexer_ListForks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListForksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IndexerServer).ListForks(m, &indexerListForksServer{stream})
+}
+
+type Indexer_ListForksServer interface {
+	Send(*Fork) error
+	grpc.ServerStream
+}
+
+type indexerListForksServer struct {
+	grpc.ServerStream
+}
+
+func (x *indexerListForksServer) Send(m *Fork) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Indexer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tezos.indexer.v1.Indexer",
+	HandlerType: (*IndexerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetHead",
+			Handler:    _Indexer_GetHead_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Indexer_GetBlock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetBalanceUpdates",
+			Handler:       _Indexer_GetBalanceUpdates_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetOperations",
+			Handler:       _Indexer_GetOperations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListForks",
+			Handler:       _Indexer_ListForks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "indexer.proto",
+}
@@ -0,0 +1,264 @@
+// Package rpc implements the gRPC API on top of the same storage as the REST one
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/rpc/pb"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"github.com/golang/protobuf/ptypes"
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxLimit = 1000000
+)
+
+// forksSource provides the list of known forks
+type forksSource interface {
+	Forks() []*storage.Fork
+}
+
+// Timeouts limit the duration of calls, zero means no limit
+type Timeouts struct {
+	Unary  time.Duration
+	Stream time.Duration
+}
+
+// Server implements pb.IndexerServer. Streams are sent while rows are read from the database: a slow reader blocks Send
+// by flow control and keeps the database connection busy until the stream timeout expires.
+type Server struct {
+	Storage  storage.Storage
+	Chain    forksSource
+	Logger   log.FieldLogger
+	Timeouts func() Timeouts // Called on each call so reloaded values are applied, optional
+}
+
+func (s *Server) log() log.FieldLogger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return log.StandardLogger()
+}
+
+func (s *Server) context(ctx context.Context, stream bool) (context.Context, context.CancelFunc) {
+	var timeout time.Duration
+	if s.Timeouts != nil {
+		t := s.Timeouts()
+		if timeout = t.Unary; stream {
+			timeout = t.Stream
+		}
+	}
+
+	if timeout != 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+var httpToGRPC = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// grpcError converts API errors into gRPC status errors
+func grpcError(err error) error {
+	code := codes.Unknown
+	if e, ok := err.(errors.Error); ok {
+		if c, ok := httpToGRPC[e.Code().Status()]; ok {
+			code = c
+		}
+	} else if err == context.DeadlineExceeded {
+		code = codes.DeadlineExceeded
+	} else if err == context.Canceled {
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
+
+func timestampProto(t time.Time) *tspb.Timestamp {
+	ts, _ := ptypes.TimestampProto(t)
+	return ts
+}
+
+func timestamp(ts *tspb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	return ptypes.Timestamp(ts)
+}
+
+func blockProto(b *storage.Block) *pb.Block {
+	return &pb.Block{
+		Hash:        b.Hash,
+		Level:       b.Level,
+		Predecessor: b.Predecessor,
+		Timestamp:   timestampProto(b.Timestamp),
+	}
+}
+
+// historyRange validates parameters shared by account history requests
+func historyRange(address string, startTS, endTS *tspb.Timestamp, limit int32) (start, end time.Time, err error) {
+	if _, err := tezos.ParseAddress(address); err != nil {
+		return start, end, status.Errorf(codes.InvalidArgument, "invalid address: %v", err)
+	}
+
+	if start, err = timestamp(startTS); err != nil {
+		return start, end, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if end, err = timestamp(endTS); err != nil {
+		return start, end, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if limit > maxLimit {
+		return start, end, status.Errorf(codes.InvalidArgument, "limit = %d exceeds maximum value of %d", limit, maxLimit)
+	}
+
+	return start, end, nil
+}
+
+// GetBalanceUpdates sends balance updates while they are read from the database
+func (s *Server) GetBalanceUpdates(req *pb.GetBalanceUpdatesRequest, stream pb.Indexer_GetBalanceUpdatesServer) error {
+	start, end, err := historyRange(req.Address, req.Start, req.End, req.Limit)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.context(stream.Context(), true)
+	defer cancel()
+
+	var sendErr error
+	err = s.Storage.EachBalanceUpdate(ctx, req.Address, start, end, int(req.Limit), func(u *storage.BalanceUpdate) error {
+		sendErr = stream.Send(&pb.BalanceUpdate{
+			Level:     u.BlockLevel,
+			BlockHash: u.BlockHash,
+			Timestamp: timestampProto(u.BlockTimestamp),
+			Diff:      u.Diff,
+			Value:     u.Value,
+		})
+		return sendErr
+	})
+
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return grpcError(err)
+	}
+	return nil
+}
+
+func operationProto(op *storage.AccountOperation) *pb.Operation {
+	res := pb.Operation{
+		Hash:      op.Hash,
+		Id:        int32(op.ID),
+		Level:     op.Level,
+		BlockHash: op.BlockHash,
+		Timestamp: timestampProto(op.Timestamp),
+		Source:    op.Source,
+	}
+
+	switch op.Kind {
+	case storage.OperationKindName(storage.OperationTransaction):
+		res.Content = &pb.Operation_Transaction{Transaction: &pb.Transaction{
+			Destination: op.Destination,
+			Amount:      op.Amount,
+			Fee:         op.Fee,
+			Parameters:  op.Parameters,
+		}}
+	case storage.OperationKindName(storage.OperationOrigination):
+		res.Content = &pb.Operation_Origination{Origination: &pb.Origination{Contract: op.Destination}}
+	case storage.OperationKindName(storage.OperationDelegation):
+		res.Content = &pb.Operation_Delegation{Delegation: &pb.Delegation{Delegate: op.Destination}}
+	}
+
+	return &res
+}
+
+// GetOperations sends operations while they are read from the database
+func (s *Server) GetOperations(req *pb.GetOperationsRequest, stream pb.Indexer_GetOperationsServer) error {
+	start, end, err := historyRange(req.Address, req.Start, req.End, req.Limit)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.context(stream.Context(), true)
+	defer cancel()
+
+	var sendErr error
+	err = s.Storage.EachOperation(ctx, req.Address, start, end, int(req.Limit), func(op *storage.AccountOperation) error {
+		sendErr = stream.Send(operationProto(op))
+		return sendErr
+	})
+
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return grpcError(err)
+	}
+	return nil
+}
+
+func (s *Server) GetHead(ctx context.Context, req *pb.GetHeadRequest) (*pb.Block, error) {
+	ctx, cancel := s.context(ctx, false)
+	defer cancel()
+
+	block, err := s.Storage.GetHead(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return blockProto(block), nil
+}
+
+func (s *Server) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.Block, error) {
+	ctx, cancel := s.context(ctx, false)
+	defer cancel()
+
+	block, err := s.Storage.GetBlock(ctx, req.Level)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return blockProto(block), nil
+}
+
+func (s *Server) ListForks(req *pb.ListForksRequest, stream pb.Indexer_ListForksServer) error {
+	for _, f := range s.Chain.Forks() {
+		fork := pb.Fork{
+			AncestorLevel: f.AncestorLevel,
+			AncestorHash:  f.AncestorHash,
+			FirstLevel:    f.FirstLevel,
+			LastLevel:     f.LastLevel,
+			Depth:         f.Depth,
+			Blocks:        make([]*pb.Block, len(f.Blocks)),
+		}
+
+		for i, b := range f.Blocks {
+			fork.Blocks[i] = blockProto(b)
+		}
+
+		if err := stream.Send(&fork); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ pb.IndexerServer = &Server{}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	"github.com/gorilla/mux"
+)
+
+func (h *Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+
+	if _, err := tezos.ParseAddress(address); err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid address", "address", err))
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	account, err := h.Storage.GetAccount(ctx, address)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	utils.ConditionalJSONResponse(w, r, account, h.headValidator(), h.MaxAge)
+}
+
+type getOriginationsRequest struct {
+	Limit int `schema:"limit" description:"Maximum number of returned originations (default 100, at most 1000)"`
+}
+
+func (h *Handler) GetOriginations(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	address := mux.Vars(r)["address"]
+
+	if _, err := tezos.ParseAddress(address); err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid address", "address", err))
+		return
+	}
+
+	req := getOriginationsRequest{
+		Limit: defaultContractsLimit,
+	}
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := checkLimit(req.Limit, maxContractsLimit); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	ret, err := h.Storage.GetOriginations(ctx, address, req.Limit)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	utils.ConditionalJSONResponse(w, r, ret, h.headValidator(), h.MaxAge)
+}
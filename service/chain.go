@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/storage/pg"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultHeadPollInterval = 10 * time.Second
+	defaultFinalityDepth    = 60
+)
+
+type chainUpdate struct {
+	Head  *storage.Block
+	Final *storage.Block
+	Forks []*storage.Fork
+	Reorg bool // The final block has been replaced
+}
+
+type chainUpdateFunc func(u *chainUpdate)
+
+// canonicalSetter is implemented by storage backends which restrict queries to the canonical chain
+type canonicalSetter interface {
+	SetHead(ctx context.Context, head *storage.Block) error
+}
+
+// chainMonitor polls the storage for the chain head and notifies subscribers about changes
+type chainMonitor struct {
+	storage   storage.ChainStorage
+	canonical canonicalSetter
+	interval  time.Duration
+	logger    log.FieldLogger
+	handlers  []chainUpdateFunc
+
+	mtx   sync.RWMutex
+	depth int64
+	head  *storage.Block
+	final *storage.Block
+	forks []*storage.Fork
+}
+
+func (c *chainMonitor) log() log.FieldLogger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return log.StandardLogger()
+}
+
+// Head returns the last seen chain head
+func (c *chainMonitor) Head() *storage.Block {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.head
+}
+
+// Final returns the most recent block considered to be immutable
+func (c *chainMonitor) Final() *storage.Block {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.final
+}
+
+// Forks returns branches which don't lead to the current head
+func (c *chainMonitor) Forks() []*storage.Fork {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.forks
+}
+
+func (c *chainMonitor) finalityDepth() int64 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.depth
+}
+
+// setFinalityDepth changes the finality depth starting from the next poll
+func (c *chainMonitor) setFinalityDepth(depth int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.depth = depth
+}
+
+func (c *chainMonitor) onUpdate(f chainUpdateFunc) {
+	c.handlers = append(c.handlers, f)
+}
+
+func (c *chainMonitor) poll(ctx context.Context) error {
+	// The head must not go back because of a lagging replica
+	ctx = pg.WithPrimary(ctx)
+
+	head, err := c.storage.GetHead(ctx)
+	if err != nil {
+		return err
+	}
+
+	prevHead, prevFinal := c.Head(), c.Final()
+	if prevHead != nil && prevHead.Hash == head.Hash {
+		return nil
+	}
+
+	// Apply canonical chain restrictions before any other query
+	if c.canonical != nil {
+		if err := c.canonical.SetHead(ctx, head); err != nil {
+			return err
+		}
+	}
+
+	// Blocks below the final one are not expected to change
+	depth := c.finalityDepth()
+	forks, err := c.storage.GetForks(ctx, head, head.Level-depth)
+	if err != nil {
+		return err
+	}
+
+	var final *storage.Block
+	if level := head.Level - depth; level >= 0 {
+		if final, err = c.storage.GetBlock(ctx, level); err != nil && err != errors.ErrResourceNotFound {
+			return err
+		}
+	}
+
+	if prevHead != nil && (head.Level <= prevHead.Level || head.Level == prevHead.Level+1 && head.Predecessor != prevHead.Hash) {
+		c.log().WithFields(log.Fields{
+			"level":         head.Level,
+			"hash":          head.Hash,
+			"previous_hash": prevHead.Hash,
+		}).Warnln("Chain reorganization detected")
+	}
+
+	// The final block is not supposed to change but check it anyway
+	var reorg bool
+	if prevFinal != nil {
+		b, err := c.storage.GetBlock(ctx, prevFinal.Level)
+		if err != nil && err != errors.ErrResourceNotFound {
+			return err
+		}
+		reorg = b == nil || b.Hash != prevFinal.Hash
+		if reorg {
+			c.log().WithField("level", prevFinal.Level).Warnln("Final block has been replaced")
+		}
+	}
+
+	if len(forks) != len(c.Forks()) {
+		c.log().WithField("forks", len(forks)).Infoln("Forks list changed")
+	}
+
+	c.mtx.Lock()
+	c.head, c.final, c.forks = head, final, forks
+	c.mtx.Unlock()
+
+	update := chainUpdate{
+		Head:  head,
+		Final: final,
+		Forks: forks,
+		Reorg: reorg,
+	}
+
+	for _, f := range c.handlers {
+		f(&update)
+	}
+
+	return nil
+}
+
+func (c *chainMonitor) run(ctx context.Context) {
+	interval := c.interval
+	if interval == 0 {
+		interval = defaultHeadPollInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := c.poll(ctx); err != nil && ctx.Err() == nil {
+			c.log().WithError(err).Errorln("Error fetching chain head")
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
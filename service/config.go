@@ -1,18 +1,149 @@
 package service
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/ecadlabs/tezos-indexer-api/middleware"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/storage/pg"
+	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
 
+// DatabaseConfig describes a member of the database cluster
+type DatabaseConfig struct {
+	URI            string `yaml:"uri"`
+	Role           string `yaml:"role"` // "primary" or "replica"
+	MaxConnections int    `yaml:"max_connections"`
+}
+
+// EndpointConfig contains per endpoint database tuning parameters
+type EndpointConfig struct {
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+	WorkMem          string        `yaml:"work_mem"`
+}
+
 type Config struct {
 	MaxConnections int           `yaml:"max_connections"`
 	Timeout        time.Duration `yaml:"timeout"`
 	URI            string        `yaml:"db_uri"`
 	HTTPAddress    string        `yaml:"http_address"`
-	LogHTTP        bool          `yaml:"log_http"`
+	GRPCAddress    string        `yaml:"grpc_address"` // gRPC API is disabled if empty
+
+	GRPCStreamTimeout time.Duration `yaml:"grpc_stream_timeout"` // Maximum duration of a streamed gRPC call, no limit if zero
+
+	// HTTPS is enabled if the certificate is set. Files are reloaded when modified.
+	TLSCertFile      string            `yaml:"tls_cert_file"`
+	TLSKeyFile       string            `yaml:"tls_key_file"`
+	TLSClientCAFile  string            `yaml:"tls_client_ca_file"` // CA bundle used to verify client certificates
+	TLSClientAuth    string            `yaml:"tls_client_auth"`    // "none", "request" or "require". Defaults to "require" if the CA bundle is set.
+	TLSCheckInterval time.Duration     `yaml:"tls_check_interval"` // Certificate files modification check interval
+	ClientIdentities map[string]string `yaml:"client_identities"`  // Client certificate subject to identity name map
+
+	// Databases takes precedence over URI and MaxConnections
+	Databases           []*DatabaseConfig `yaml:"databases"`
+	MaxReplicaLag       time.Duration     `yaml:"max_replica_lag"`       // Replicas lagging behind more than this are not used
+	HealthCheckInterval time.Duration     `yaml:"health_check_interval"` // Replicas health check interval
+
+	LogLevel     string    `yaml:"log_level"` // Defaults to info
+	LogHTTP      bool      `yaml:"log_http"`
+	LegacySunset time.Time `yaml:"legacy_sunset"`  // Sunset date of legacy unversioned routes, optional
+	Production   bool      `yaml:"production"`     // Hide internal error details from clients
+	ErrorDocsURL string    `yaml:"error_docs_url"` // Error codes documentation URL included into error responses
+	RedocFile    string    `yaml:"redoc_file"`     // Redoc standalone bundle served with the API documentation
+
+	StatementTimeout time.Duration              `yaml:"statement_timeout"` // Default server side statement timeout
+	WorkMem          string                     `yaml:"work_mem"`          // Default work_mem value
+	Endpoints        map[string]*EndpointConfig `yaml:"endpoints"`         // Per endpoint overrides keyed by route name
+
+	// Cross-origin requests are disabled if no origins are allowed
+	CORSAllowedOrigins   []string      `yaml:"cors_allowed_origins"` // "*" allows any origin, not allowed with credentials
+	CORSAllowedMethods   []string      `yaml:"cors_allowed_methods"` // Defaults to GET and HEAD
+	CORSAllowedHeaders   []string      `yaml:"cors_allowed_headers"`
+	CORSExposedHeaders   []string      `yaml:"cors_exposed_headers"`
+	CORSAllowCredentials bool          `yaml:"cors_allow_credentials"`
+	CORSMaxAge           time.Duration `yaml:"cors_max_age"` // Preflight response cache lifetime
+
+	CompressionEncodings []string       `yaml:"compression_encodings"` // Response content codings in order of preference, compression is disabled if empty
+	CompressionLevels    map[string]int `yaml:"compression_levels"`    // Encoder specific levels keyed by content coding
+	CompressionMinSize   int            `yaml:"compression_min_size"`  // Shorter responses are not compressed
+
+	CacheSize        int           `yaml:"cache_size"`         // Maximum number of cached responses, 0 disables caching
+	HeadPollInterval time.Duration `yaml:"head_poll_interval"` // Chain head polling interval
+	FinalityDepth    int           `yaml:"finality_depth"`     // Number of blocks after which a block is considered immutable
+	CacheMaxAge      time.Duration `yaml:"cache_max_age"`      // HTTP Cache-Control max-age for non-final responses
+
+	StatsInterval time.Duration `yaml:"stats_interval"` // Rich list and volume rankings recomputation interval
+	StatsSize     int           `yaml:"stats_size"`     // Number of precomputed entries per ranking
+	FeeWindow     int           `yaml:"fee_window"`     // Number of recent blocks fee statistics are computed over
+
+	LabelsFile      string   `yaml:"labels_file"`      // Address labels YAML file, labels are disabled if empty
+	AdminIdentities []string `yaml:"admin_identities"` // Client identities allowed to modify labels
+}
+
+// defaultEndpoints contains built in per endpoint parameters which can be overridden by Config.Endpoints
+var defaultEndpoints = map[string]*EndpointConfig{
+	// Prefix search may be slow without pattern indices so keep it short
+	"search": {StatementTimeout: 2 * time.Second},
+}
+
+// databases returns the list of configured database cluster members
+func (c *Config) databases() []*DatabaseConfig {
+	if len(c.Databases) != 0 {
+		return c.Databases
+	}
+	return []*DatabaseConfig{
+		{
+			URI:            c.URI,
+			Role:           pg.RolePrimary,
+			MaxConnections: c.MaxConnections,
+		},
+	}
+}
+
+// queryOptions returns database tuning parameters keyed by route name. The default ones are stored under the empty key.
+func (c *Config) queryOptions() map[string]*storage.QueryOptions {
+	def := &storage.QueryOptions{
+		StatementTimeout: c.StatementTimeout,
+		WorkMem:          c.WorkMem,
+	}
+
+	endpoints := make(map[string]*EndpointConfig, len(defaultEndpoints)+len(c.Endpoints))
+	for name, ep := range defaultEndpoints {
+		endpoints[name] = ep
+	}
+	for name, ep := range c.Endpoints {
+		endpoints[name] = ep
+	}
+
+	res := map[string]*storage.QueryOptions{"": def}
+	for name, ep := range endpoints {
+		opt := *def
+		if ep.StatementTimeout != 0 {
+			opt.StatementTimeout = ep.StatementTimeout
+		}
+		if ep.WorkMem != "" {
+			opt.WorkMem = ep.WorkMem
+		}
+		res[name] = &opt
+	}
+
+	return res
+}
+
+// Level returns the parsed log level
+func (c *Config) Level() (log.Level, error) {
+	if c.LogLevel == "" {
+		return log.InfoLevel, nil
+	}
+	return log.ParseLevel(c.LogLevel)
 }
 
 func (c *Config) Load(name string) error {
@@ -23,3 +154,197 @@ func (c *Config) Load(name string) error {
 
 	return yaml.Unmarshal(buf, c)
 }
+
+// EnvPrefix is prepended to upper cased YAML keys to get environment variable names, e.g. TEZOS_INDEXER_API_DB_URI
+const EnvPrefix = "TEZOS_INDEXER_API_"
+
+// LoadEnv overrides values from environment variables. Non string values are parsed as YAML.
+func (c *Config) LoadEnv() error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	var errs ConfigError
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+
+		name := EnvPrefix + strings.ToUpper(key)
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		f := v.Field(i)
+		if f.Kind() == reflect.String {
+			f.SetString(val)
+			continue
+		}
+
+		if err := yaml.Unmarshal([]byte(val), f.Addr().Interface()); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// ConfigError contains all configuration problems found
+type ConfigError []string
+
+func (c ConfigError) Error() string {
+	return "invalid configuration:\n\t" + strings.Join(c, "\n\t")
+}
+
+// Validate returns ConfigError listing all problems at once
+func (c *Config) Validate() error {
+	var errs ConfigError
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.HTTPAddress != "", "http_address: required")
+	check(c.GRPCAddress == "" || c.GRPCAddress != c.HTTPAddress, "grpc_address: must differ from http_address")
+	check(c.MaxConnections > 0, "max_connections: must be positive")
+
+	if _, err := c.Level(); err != nil {
+		check(false, "log_level: %v", err)
+	}
+
+	if len(c.Databases) == 0 {
+		check(c.URI != "", "db_uri: required")
+	} else {
+		var primaries int
+		for i, db := range c.Databases {
+			check(db.URI != "", "databases[%d].uri: required", i)
+			check(db.MaxConnections >= 0, "databases[%d].max_connections: must not be negative", i)
+
+			switch db.Role {
+			case pg.RolePrimary, "":
+				primaries++
+			case pg.RoleReplica:
+			default:
+				check(false, "databases[%d].role: unknown role %q", i, db.Role)
+			}
+		}
+		check(primaries == 1, "databases: exactly one primary is required, got %d", primaries)
+	}
+
+	if c.TLSCertFile != "" {
+		check(c.TLSKeyFile != "", "tls_key_file: required if tls_cert_file is set")
+	} else {
+		check(c.TLSKeyFile == "" && c.TLSClientCAFile == "", "tls_cert_file: required if tls_key_file or tls_client_ca_file is set")
+	}
+
+	switch c.TLSClientAuth {
+	case "", ClientAuthNone:
+	case ClientAuthRequest, ClientAuthRequire:
+		check(c.TLSClientCAFile != "", "tls_client_ca_file: required if tls_client_auth is %q", c.TLSClientAuth)
+	default:
+		check(false, "tls_client_auth: unknown mode %q", c.TLSClientAuth)
+	}
+
+	durations := []struct {
+		name string
+		val  time.Duration
+	}{
+		{"timeout", c.Timeout},
+		{"grpc_stream_timeout", c.GRPCStreamTimeout},
+		{"statement_timeout", c.StatementTimeout},
+		{"max_replica_lag", c.MaxReplicaLag},
+		{"health_check_interval", c.HealthCheckInterval},
+		{"head_poll_interval", c.HeadPollInterval},
+		{"cache_max_age", c.CacheMaxAge},
+		{"tls_check_interval", c.TLSCheckInterval},
+		{"cors_max_age", c.CORSMaxAge},
+		{"stats_interval", c.StatsInterval},
+	}
+	for _, d := range durations {
+		check(d.val >= 0, "%s: must not be negative", d.name)
+	}
+
+	for name, ep := range c.Endpoints {
+		check(ep != nil && ep.StatementTimeout >= 0, "endpoints.%s.statement_timeout: must not be negative", name)
+	}
+
+	for i, enc := range c.CompressionEncodings {
+		switch enc {
+		case middleware.EncodingBrotli, middleware.EncodingZstd, middleware.EncodingGzip:
+		default:
+			check(false, "compression_encodings[%d]: unknown content coding %q", i, enc)
+		}
+	}
+	for enc, level := range c.CompressionLevels {
+		if err := middleware.CheckLevel(enc, level); err != nil {
+			check(false, "compression_levels.%s: %v", enc, err)
+		}
+	}
+	check(c.CompressionMinSize >= 0, "compression_min_size: must not be negative")
+
+	if c.CORSAllowCredentials {
+		for _, o := range c.CORSAllowedOrigins {
+			check(o != "*", "cors_allowed_origins: \"*\" can't be combined with cors_allow_credentials, list the origins explicitly")
+		}
+	}
+
+	for subject, name := range c.ClientIdentities {
+		check(name != "" && !strings.HasPrefix(name, middleware.UnlistedPrefix), "client_identities.%s: must not be empty or start with %q", subject, middleware.UnlistedPrefix)
+	}
+	for i, name := range c.AdminIdentities {
+		check(!strings.HasPrefix(name, middleware.UnlistedPrefix), "admin_identities[%d]: names starting with %q are not mapped by client_identities", i, middleware.UnlistedPrefix)
+	}
+
+	check(c.CacheSize >= 0, "cache_size: must not be negative")
+	check(c.FinalityDepth >= 0, "finality_depth: must not be negative")
+	check(c.StatsSize >= 0, "stats_size: must not be negative")
+	check(c.FeeWindow >= 0, "fee_window: must not be negative")
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+const maskedSecret = "xxxxx"
+
+// Matches sslpassword too
+var dsnPasswordRegexp = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+func maskURI(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), maskedSecret)
+		}
+		q := u.Query()
+		for _, k := range []string{"password", "sslpassword"} {
+			if _, ok := q[k]; ok {
+				q.Set(k, maskedSecret)
+				u.RawQuery = q.Encode()
+			}
+		}
+		return u.String()
+	}
+	// key=value connection string
+	return dsnPasswordRegexp.ReplaceAllString(uri, "${1}"+maskedSecret)
+}
+
+// Masked returns a copy of the configuration with secrets masked
+func (c *Config) Masked() *Config {
+	res := *c
+	res.URI = maskURI(c.URI)
+
+	res.Databases = make([]*DatabaseConfig, len(c.Databases))
+	for i, db := range c.Databases {
+		d := *db
+		d.URI = maskURI(db.URI)
+		res.Databases[i] = &d
+	}
+
+	return &res
+}
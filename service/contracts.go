@@ -0,0 +1,52 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+// Contract and origination listings are page sized unlike balance histories bounded by maxLimit
+const (
+	defaultContractsLimit = 100
+	maxContractsLimit     = 1000
+)
+
+type getContractsRequest struct {
+	Manager string `schema:"manager,required" description:"Manager address"`
+	Limit   int    `schema:"limit" description:"Maximum number of returned contracts (default 100, at most 1000)"`
+}
+
+func (h *Handler) GetContracts(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getContractsRequest{
+		Limit: defaultContractsLimit,
+	}
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if _, err := tezos.ParseAddress(req.Manager); err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid query parameters", "manager", err))
+		return
+	}
+
+	if err := checkLimit(req.Limit, maxContractsLimit); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	ret, err := h.Storage.GetContracts(ctx, req.Manager, req.Limit)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	utils.ConditionalJSONResponse(w, r, ret, h.headValidator(), h.MaxAge)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultCyclesLimit = 10
+	maxCyclesLimit     = 100
+)
+
+type getCyclesRequest struct {
+	Before int64 `schema:"before" description:"Return cycles preceding this one"`
+	Limit  int   `schema:"limit" description:"Maximum number of returned cycles (default 10)"`
+}
+
+func (h *Handler) GetCycles(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getCyclesRequest{
+		Before: -1,
+		Limit:  defaultCyclesLimit,
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := checkLimit(req.Limit, maxCyclesLimit); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	ret, err := h.Storage.GetCycles(ctx, req.Before, req.Limit)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	val := &utils.Validator{}
+	if len(ret) != 0 {
+		val = h.rangeValidator(ret[0].LastLevel, ret[0].LastHash)
+		val.Timestamp = ret[0].EndTime
+	}
+
+	utils.ConditionalJSONResponse(w, r, ret, val, h.MaxAge)
+}
+
+func (h *Handler) GetCycle(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.ParseInt(mux.Vars(r)["cycle"], 10, 64)
+	if err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid path parameters", "cycle", err))
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	cycle, err := h.Storage.GetCycle(ctx, n)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	val := h.rangeValidator(cycle.LastLevel, cycle.LastHash)
+	val.Timestamp = cycle.EndTime
+
+	utils.ConditionalJSONResponse(w, r, cycle, val, h.MaxAge)
+}
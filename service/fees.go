@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultFeeWindow = 60
+
+// FeeStats contains fee distribution of an operation kind, all values are in mutez
+type FeeStats struct {
+	Count int64 `json:"count"`
+	Min   int64 `json:"min"`
+	Max   int64 `json:"max"`
+	Mean  int64 `json:"mean"`
+	P10   int64 `json:"p10"`
+	P25   int64 `json:"p25"`
+	P50   int64 `json:"p50"`
+	P75   int64 `json:"p75"`
+	P90   int64 `json:"p90"`
+	P99   int64 `json:"p99"`
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []int64, p float64) int64 {
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func newFeeStats(fees []int64) *FeeStats {
+	if len(fees) == 0 {
+		return &FeeStats{}
+	}
+
+	sorted := make([]int64, len(fees))
+	copy(sorted, fees)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum float64
+	for _, f := range sorted {
+		sum += float64(f)
+	}
+
+	return &FeeStats{
+		Count: int64(len(sorted)),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		Mean:  int64(math.Round(sum / float64(len(sorted)))),
+		P10:   percentile(sorted, 10),
+		P25:   percentile(sorted, 25),
+		P50:   percentile(sorted, 50),
+		P75:   percentile(sorted, 75),
+		P90:   percentile(sorted, 90),
+		P99:   percentile(sorted, 99),
+	}
+}
+
+// feeSnapshot contains fee statistics of the recent blocks up to the given one
+type feeSnapshot struct {
+	Level     int64
+	Hash      string
+	Timestamp time.Time
+	Blocks    int64
+	Kinds     map[string]*FeeStats // Keyed by operation kind name
+}
+
+// feeTracker maintains fee statistics of a sliding window of blocks. Only new blocks are fetched on a chain head change.
+type feeTracker struct {
+	storage storage.FeeStorage
+	chain   chainState
+	window  int64
+	logger  log.FieldLogger
+	notify  chan struct{}
+
+	blocks []*storage.BlockFees // Owned by the run loop, ascending levels without gaps
+
+	mtx      sync.RWMutex
+	snapshot *feeSnapshot
+}
+
+func newFeeTracker(s storage.FeeStorage, chain chainState, window int, logger log.FieldLogger) *feeTracker {
+	if window == 0 {
+		window = defaultFeeWindow
+	}
+	return &feeTracker{
+		storage: s,
+		chain:   chain,
+		window:  int64(window),
+		logger:  logger,
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+func (f *feeTracker) log() log.FieldLogger {
+	if f.logger != nil {
+		return f.logger
+	}
+	return log.StandardLogger()
+}
+
+// Snapshot returns the most recent statistics or nil if they are not computed yet
+func (f *feeTracker) Snapshot() *feeSnapshot {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return f.snapshot
+}
+
+// onChainUpdate schedules an update without blocking the chain monitor
+func (f *feeTracker) onChainUpdate(*chainUpdate) {
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (f *feeTracker) update(ctx context.Context) error {
+	head := f.chain.Head()
+	if head == nil {
+		return nil
+	}
+	if prev := f.Snapshot(); prev != nil && prev.Hash == head.Hash {
+		return nil
+	}
+
+	from := head.Level - f.window + 1
+	if from < 0 {
+		from = 0
+	}
+
+	// Keep the contiguous run of blocks at the start of the window below the head, the head itself is always refetched
+	var kept []*storage.BlockFees
+	next := from
+	for _, b := range f.blocks {
+		if b.Level < from {
+			continue
+		}
+		if b.Level != next || b.Level >= head.Level {
+			break
+		}
+		kept = append(kept, b)
+		next++
+	}
+
+	blocks, err := f.storage.GetBlockFees(ctx, next, head.Level)
+	if err != nil {
+		return err
+	}
+	// Fetched blocks are canonical. If they don't continue the kept ones then some of those were reorganised away,
+	// possibly deeper than the forks tracked by the chain monitor, so the whole window is refetched.
+	if len(kept) != 0 && (len(blocks) == 0 || blocks[0].Predecessor != kept[len(kept)-1].Hash) {
+		if blocks, err = f.storage.GetBlockFees(ctx, from, head.Level); err != nil {
+			return err
+		}
+		kept = nil
+	}
+	kept = append(kept, blocks...)
+	f.blocks = kept
+
+	// Kinds without fees in the window are reported with zero count
+	fees := make(map[int][]int64, len(storage.FeeKinds))
+	for _, kind := range storage.FeeKinds {
+		fees[kind] = nil
+	}
+	for _, b := range kept {
+		for kind, v := range b.Fees {
+			fees[kind] = append(fees[kind], v...)
+		}
+	}
+
+	snap := feeSnapshot{
+		Level:     head.Level,
+		Hash:      head.Hash,
+		Timestamp: head.Timestamp,
+		Blocks:    int64(len(kept)),
+		Kinds:     make(map[string]*FeeStats, len(fees)),
+	}
+	for kind, v := range fees {
+		snap.Kinds[storage.OperationKindName(kind)] = newFeeStats(v)
+	}
+
+	f.mtx.Lock()
+	f.snapshot = &snap
+	f.mtx.Unlock()
+
+	return nil
+}
+
+func (f *feeTracker) run(ctx context.Context) {
+	for {
+		if err := f.update(ctx); err != nil && ctx.Err() == nil {
+			f.log().WithError(err).Errorln("Error updating fee statistics")
+		}
+
+		select {
+		case <-f.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// feeSource provides fee statistics of the recent blocks
+type feeSource interface {
+	Snapshot() *feeSnapshot
+}
+
+type feeStatsResponse struct {
+	Level     int64                `json:"level"`
+	Hash      string               `json:"hash"`
+	Timestamp time.Time            `json:"timestamp"`
+	Blocks    int64                `json:"blocks"` // Number of blocks the statistics are computed over
+	Kinds     map[string]*FeeStats `json:"kinds"`  // Keyed by operation kind
+}
+
+type feeSuggestionResponse struct {
+	Level     int64     `json:"level"`
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Low       int64     `json:"low"`    // 25th percentile
+	Medium    int64     `json:"medium"` // Median
+	High      int64     `json:"high"`   // 90th percentile
+}
+
+type getFeeSuggestionRequest struct {
+	Kind string `schema:"kind" description:"Operation kind: transaction, origination or delegation (default transaction)"`
+}
+
+func (h *Handler) feeSnapshot() (*feeSnapshot, error) {
+	if h.Fees == nil {
+		return nil, errors.ErrNotReady
+	}
+	snap := h.Fees.Snapshot()
+	if snap == nil {
+		return nil, errors.ErrNotReady
+	}
+	return snap, nil
+}
+
+func feeValidator(snap *feeSnapshot) *utils.Validator {
+	return &utils.Validator{
+		Level:     snap.Level,
+		Hash:      snap.Hash,
+		Timestamp: snap.Timestamp,
+	}
+}
+
+func (h *Handler) GetFeeStats(w http.ResponseWriter, r *http.Request) {
+	snap, err := h.feeSnapshot()
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	res := feeStatsResponse{
+		Level:     snap.Level,
+		Hash:      snap.Hash,
+		Timestamp: snap.Timestamp,
+		Blocks:    snap.Blocks,
+		Kinds:     snap.Kinds,
+	}
+
+	utils.ConditionalJSONResponse(w, r, &res, feeValidator(snap), h.MaxAge)
+}
+
+func (h *Handler) GetFeeSuggestion(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getFeeSuggestionRequest{
+		Kind: storage.OperationKindName(storage.OperationTransaction),
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	snap, err := h.feeSnapshot()
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	stats, ok := snap.Kinds[req.Kind]
+	if !ok {
+		utils.JSONError(w, r, invalidParam("Invalid query parameters", "kind", fmt.Errorf("no fee data for operation kind %q", req.Kind)))
+		return
+	}
+	if stats.Count == 0 {
+		// Nothing to suggest from until an operation of the kind is included in the window
+		utils.JSONError(w, r, errors.ErrResourceNotFound)
+		return
+	}
+
+	res := feeSuggestionResponse{
+		Level:     snap.Level,
+		Hash:      snap.Hash,
+		Timestamp: snap.Timestamp,
+		Kind:      req.Kind,
+		Low:       stats.P25,
+		Medium:    stats.P50,
+		High:      stats.P90,
+	}
+
+	utils.ConditionalJSONResponse(w, r, &res, feeValidator(snap), h.MaxAge)
+}
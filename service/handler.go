@@ -4,10 +4,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ecadlabs/tezos-indexer-api/errors"
-	"github.com/ecadlabs/tezos-indexer-api/storage/pg"
+	"github.com/ecadlabs/tezos-indexer-api/labels"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
 	"github.com/ecadlabs/tezos-indexer-api/utils"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
@@ -18,10 +25,27 @@ const (
 	maxLimit = 1000000
 )
 
+// chainState provides the current chain head and the most recent immutable block
+type chainState interface {
+	Head() *storage.Block
+	Final() *storage.Block
+	Forks() []*storage.Fork
+}
+
 type Handler struct {
-	Storage *pg.PostgresStorage
+	Storage storage.Storage
+	Chain   chainState
+	Stats   statsSource
+	Fees    feeSource
+	Labels  *labels.Store // Optional
 	Logger  log.FieldLogger
 	Timeout time.Duration
+	MaxAge  time.Duration // Cache-Control max-age for responses which may change with new blocks
+
+	// Database tuning parameters keyed by route name, the default ones are stored under the empty key
+	QueryOptions map[string]*storage.QueryOptions
+
+	AdminIdentities []string // Client identities allowed to use administrative endpoints
 }
 
 func (h *Handler) log() log.FieldLogger {
@@ -31,37 +55,163 @@ func (h *Handler) log() log.FieldLogger {
 	return log.StandardLogger()
 }
 
+func (h *Handler) queryOptions(r *http.Request) *storage.QueryOptions {
+	if route := mux.CurrentRoute(r); route != nil {
+		if opt, ok := h.QueryOptions[route.GetName()]; ok {
+			return opt
+		}
+	}
+	return h.QueryOptions[""]
+}
+
 func (h *Handler) context(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx := r.Context()
+	if opt := h.queryOptions(r); opt != nil {
+		ctx = storage.WithQueryOptions(ctx, opt)
+	}
+
 	if h.Timeout != 0 {
-		return context.WithTimeout(r.Context(), h.Timeout)
+		return context.WithTimeout(ctx, h.Timeout)
 	}
-	return r.Context(), func() {}
+	return ctx, func() {}
 }
 
-var schemaDecoder = schema.NewDecoder()
+var (
+	schemaDecoder = schema.NewDecoder()
+	timeType      = reflect.TypeOf(time.Time{})
+)
 
-func (h *Handler) GetBalanceUpdate(w http.ResponseWriter, r *http.Request) {
-	type getBalanceUpdateRequest struct {
-		Start   time.Time `schema:"start"`
-		End     time.Time `schema:"end"`
-		Limit   int       `schema:"limit"`
-		Compact bool      `schema:"compact"`
+func typeDescription(t reflect.Type) string {
+	if t == timeType {
+		return "RFC 3339 timestamp"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	}
+	return t.String()
+}
+
+func fieldError(key string, err error) *errors.FieldError {
+	reason := err.Error()
+
+	switch e := err.(type) {
+	case schema.ConversionError:
+		reason = "expected " + typeDescription(e.Type)
+		if e.Err != nil {
+			reason += ": " + e.Err.Error()
+		}
+
+	case schema.UnknownKeyError:
+		reason = "unknown parameter"
+
+	case schema.EmptyFieldError:
+		reason = "required parameter is missing"
 	}
 
+	return &errors.FieldError{Field: key, Reason: reason}
+}
+
+// invalidParam returns a validation error for a single parameter
+func invalidParam(msg, field string, err error) error {
+	return errors.NewValidationError(msg, errors.CodeBadRequest, &errors.FieldError{
+		Field:  field,
+		Reason: strings.TrimPrefix(err.Error(), "tezos: "),
+	})
+}
+
+// decodeQuery decodes the query string into dst reporting each invalid parameter
+func decodeQuery(dst interface{}, src url.Values) error {
+	err := schemaDecoder.Decode(dst, src)
+	if err == nil {
+		return nil
+	}
+
+	var fields []*errors.FieldError
+	if me, ok := err.(schema.MultiError); ok {
+		for key, e := range me {
+			fields = append(fields, fieldError(key, e))
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	} else {
+		fields = []*errors.FieldError{{Reason: err.Error()}}
+	}
+
+	return errors.NewValidationError("Invalid query parameters", errors.CodeBadRequest, fields...)
+}
+
+func checkLimit(limit, max int) error {
+	if limit > max {
+		return errors.NewValidationError("Limit is too big", errors.CodeLimitTooBig, &errors.FieldError{
+			Field:  "limit",
+			Reason: fmt.Sprintf("%d exceeds maximum value of %d", limit, max),
+		})
+	}
+	return nil
+}
+
+// headValidator returns the validator for a response which may change with any new block
+func (h *Handler) headValidator() *utils.Validator {
+	val := utils.Validator{}
+	if head := h.Chain.Head(); head != nil {
+		val.Level = head.Level
+		val.Hash = head.Hash
+		val.Timestamp = head.Timestamp
+	}
+	return &val
+}
+
+// rangeValidator returns the validator for a response covering levels up to the given block
+func (h *Handler) rangeValidator(level int64, hash string) *utils.Validator {
+	val := utils.Validator{
+		Level: level,
+		Hash:  hash,
+	}
+	// The range is complete if there are immutable blocks after its last one
+	if final := h.Chain.Final(); final != nil {
+		val.Final = level < final.Level
+	}
+	return &val
+}
+
+type getBalanceUpdateRequest struct {
+	Start   time.Time `schema:"start" description:"Start of the time range (inclusive)"`
+	End     time.Time `schema:"end" description:"End of the time range (exclusive)"`
+	Limit   int       `schema:"limit" description:"Maximum number of returned updates"`
+	Compact bool      `schema:"compact" description:"Return columnar arrays instead of an array of objects (default true)"`
+}
+
+type compactBalanceUpdate struct {
+	BlockLevel     []int64     `json:"level"`
+	BlockTimestamp []time.Time `json:"timestamp"`
+	Diff           []int64     `json:"diff"`
+	Value          []int64     `json:"value"`
+}
+
+func (h *Handler) GetBalanceUpdate(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	pkh := mux.Vars(r)["pkh"]
 
+	if _, err := tezos.ParseAddress(pkh); err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid address", "pkh", err))
+		return
+	}
+
 	req := getBalanceUpdateRequest{
 		Compact: true,
 	}
 
-	if err := schemaDecoder.Decode(&req, r.Form); err != nil {
-		utils.JSONError(w, errors.Wrap(err, errors.CodeBadRequest))
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
 		return
 	}
 
-	if req.Limit > maxLimit {
-		utils.JSONError(w, errors.New(fmt.Sprintf("limit = %d exceeds maximum value of %d", req.Limit, maxLimit), errors.CodeLimitTooBig))
+	if err := checkLimit(req.Limit, maxLimit); err != nil {
+		utils.JSONError(w, r, err)
 		return
 	}
 
@@ -70,18 +220,25 @@ func (h *Handler) GetBalanceUpdate(w http.ResponseWriter, r *http.Request) {
 
 	ret, err := h.Storage.GetBalanceUpdate(ctx, pkh, req.Start, req.End, req.Limit)
 	if err != nil {
-		utils.JSONError(w, err)
+		utils.JSONError(w, r, err)
 		return
 	}
 
-	if req.Compact {
-		type compactBalanceUpdate struct {
-			BlockLevel     []int64     `json:"level"`
-			BlockTimestamp []time.Time `json:"timestamp"`
-			Diff           []int64     `json:"diff"`
-			Value          []int64     `json:"value"`
-		}
+	// Results are returned in descending order
+	val := utils.Validator{}
+	if len(ret) != 0 {
+		val.Level = ret[0].BlockLevel
+		val.Hash = ret[0].BlockHash
+		val.Timestamp = ret[0].BlockTimestamp
+	}
+	if final := h.Chain.Final(); final != nil && !req.End.IsZero() {
+		val.Final = !req.End.After(final.Timestamp)
+	}
+
+	// Balance updates contain no addresses, rewriting up to maxLimit of them would be wasted
+	r = r.WithContext(utils.WithoutEnricher(r.Context()))
 
+	if req.Compact {
 		compacted := compactBalanceUpdate{
 			BlockLevel:     make([]int64, len(ret)),
 			BlockTimestamp: make([]time.Time, len(ret)),
@@ -96,9 +253,58 @@ func (h *Handler) GetBalanceUpdate(w http.ResponseWriter, r *http.Request) {
 			compacted.Value[i] = u.Value
 		}
 
-		utils.JSONResponse(w, http.StatusOK, &compacted)
+		utils.ConditionalJSONResponse(w, r, &compacted, &val, h.MaxAge)
+		return
+	}
+
+	utils.ConditionalJSONResponse(w, r, ret, &val, h.MaxAge)
+}
+
+func (h *Handler) GetHead(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	block, err := h.Storage.GetHead(ctx)
+	if err != nil {
+		utils.JSONError(w, r, err)
 		return
 	}
 
-	utils.JSONResponse(w, http.StatusOK, ret)
+	utils.JSONResponse(w, http.StatusOK, block)
+}
+
+func (h *Handler) GetBlock(w http.ResponseWriter, r *http.Request) {
+	level, err := strconv.ParseInt(mux.Vars(r)["level"], 10, 64)
+	if err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid path parameters", "level", err))
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	block, err := h.Storage.GetBlock(ctx, level)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	val := utils.Validator{
+		Level:     block.Level,
+		Hash:      block.Hash,
+		Timestamp: block.Timestamp,
+	}
+	if final := h.Chain.Final(); final != nil {
+		val.Final = block.Level <= final.Level
+	}
+
+	utils.ConditionalJSONResponse(w, r, block, &val, h.MaxAge)
+}
+
+func (h *Handler) GetForks(w http.ResponseWriter, r *http.Request) {
+	forks := h.Chain.Forks()
+	if forks == nil {
+		forks = []*storage.Fork{}
+	}
+	utils.JSONResponse(w, http.StatusOK, forks)
 }
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/labels"
+	"github.com/ecadlabs/tezos-indexer-api/middleware"
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	"github.com/gorilla/mux"
+)
+
+const maxLabelBodySize = 64 * 1024
+
+var errLabelsDisabled = errors.New("Labels are not configured", errors.CodeEndpointNotFound)
+
+type getLabelsRequest struct {
+	Tag string `schema:"tag" description:"Return only labels having the tag"`
+}
+
+// putLabelRequest is the label update body, the address is taken from the path
+type putLabelRequest struct {
+	Label string   `json:"label"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// admin restricts the handler to configured client identities
+func (h *Handler) admin(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := middleware.RequireIdentity{Names: h.AdminIdentities}
+		auth.Handler(f).ServeHTTP(w, r)
+	}
+}
+
+// labelAddress returns the normalized address path parameter
+func labelAddress(r *http.Request) (string, error) {
+	a, err := tezos.ParseAddress(mux.Vars(r)["address"])
+	if err != nil {
+		return "", invalidParam("Invalid address", "address", err)
+	}
+	return a.String(), nil
+}
+
+func (h *Handler) GetLabels(w http.ResponseWriter, r *http.Request) {
+	if h.Labels == nil {
+		utils.JSONError(w, r, errLabelsDisabled)
+		return
+	}
+
+	r.ParseForm()
+
+	var req getLabelsRequest
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, h.Labels.List(req.Tag))
+}
+
+func (h *Handler) GetLabel(w http.ResponseWriter, r *http.Request) {
+	if h.Labels == nil {
+		utils.JSONError(w, r, errLabelsDisabled)
+		return
+	}
+
+	address, err := labelAddress(r)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	l := h.Labels.Get(address)
+	if l == nil {
+		utils.JSONError(w, r, errors.ErrResourceNotFound)
+		return
+	}
+
+	utils.JSONResponse(w, http.StatusOK, l)
+}
+
+func (h *Handler) PutLabel(w http.ResponseWriter, r *http.Request) {
+	if h.Labels == nil {
+		utils.JSONError(w, r, errLabelsDisabled)
+		return
+	}
+
+	address, err := labelAddress(r)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	var req putLabelRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLabelBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		utils.JSONError(w, r, errors.NewValidationError("Invalid request body", errors.CodeBadRequest, &errors.FieldError{Reason: err.Error()}))
+		return
+	}
+
+	l := labels.Label{
+		Address: address,
+		Label:   req.Label,
+		Tags:    req.Tags,
+	}
+
+	created, err := h.Labels.Put(&l)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	logger := h.log().WithField("address", address)
+	if id := utils.GetIdentity(r.Context()); id != nil {
+		logger = logger.WithField("identity", id.Name)
+	}
+	logger.Infoln("Label updated")
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	utils.JSONResponse(w, status, &l)
+}
+
+func (h *Handler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	if h.Labels == nil {
+		utils.JSONError(w, r, errLabelsDisabled)
+		return
+	}
+
+	address, err := labelAddress(r)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := h.Labels.Delete(address); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	logger := h.log().WithField("address", address)
+	if id := utils.GetIdentity(r.Context()); id != nil {
+		logger = logger.WithField("identity", id.Name)
+	}
+	logger.Infoln("Label deleted")
+
+	w.WriteHeader(http.StatusNoContent)
+}
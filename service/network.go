@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+const (
+	defaultNetworkStatsLimit = 30
+	maxNetworkStatsLimit     = 1000
+)
+
+type getNetworkStatsRequest struct {
+	Interval string    `schema:"interval" description:"Bucket size: hour, day, week or month (default day)"`
+	Start    time.Time `schema:"start" description:"Start of the time range, rounded down to the bucket start. Ranges longer than limit buckets are shortened"`
+	End      time.Time `schema:"end" description:"End of the time range (exclusive, default now)"`
+	Limit    int       `schema:"limit" description:"Maximum number of returned buckets (default 30)"`
+}
+
+// intervalsBefore returns the time n buckets before t
+func intervalsBefore(t time.Time, interval string, n int) time.Time {
+	switch interval {
+	case storage.IntervalHour:
+		return t.Add(-time.Duration(n) * time.Hour)
+	case storage.IntervalWeek:
+		return t.AddDate(0, 0, -7*n)
+	case storage.IntervalMonth:
+		return t.AddDate(0, -n, 0)
+	}
+	return t.AddDate(0, 0, -n)
+}
+
+func (h *Handler) GetNetworkStats(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getNetworkStatsRequest{
+		Interval: storage.IntervalDay,
+		Limit:    defaultNetworkStatsLimit,
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	switch req.Interval {
+	case storage.IntervalHour, storage.IntervalDay, storage.IntervalWeek, storage.IntervalMonth:
+	default:
+		utils.JSONError(w, r, invalidParam("Invalid query parameters", "interval", fmt.Errorf("unknown interval %q", req.Interval)))
+		return
+	}
+
+	if err := checkLimit(req.Limit, maxNetworkStatsLimit); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if req.End.IsZero() {
+		req.End = time.Now()
+	}
+
+	if !req.Start.IsZero() && !req.Start.Before(req.End) {
+		utils.JSONError(w, r, errors.NewValidationError("Invalid time range", errors.CodeBadRequest, &errors.FieldError{
+			Field:  "start",
+			Reason: "must precede end",
+		}))
+		return
+	}
+
+	// Only the last limit buckets are returned so there is no point in scanning blocks before them
+	if min := intervalsBefore(req.End, req.Interval, req.Limit); req.Start.Before(min) {
+		req.Start = min
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	ret, err := h.Storage.GetNetworkStats(ctx, req.Interval, req.Start.UTC(), req.End.UTC(), req.Limit)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	val := &utils.Validator{}
+	if len(ret) != 0 {
+		val = h.rangeValidator(ret[0].LastLevel, ret[0].LastHash)
+	}
+
+	utils.ConditionalJSONResponse(w, r, ret, val, h.MaxAge)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/openapi"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+// docsPage loads Redoc from the service itself so the documentation doesn't depend on third party hosts
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Tezos Indexer API</title>
+	<meta charset="utf-8"/>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+	<redoc spec-url="openapi.json"></redoc>
+	<script src="redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+var errRedocDisabled = errors.New("Redoc bundle is not configured", errors.CodeEndpointNotFound)
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}:]+)(?::([^}]+))?\}`)
+
+func pathParameters(path string) (string, []*openapi.Parameter) {
+	var params []*openapi.Parameter
+
+	for _, m := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		schema := &openapi.Schema{Type: "string"}
+		if m[2] == "[0-9]+" {
+			schema = &openapi.Schema{Type: "integer", Format: "int64"}
+		} else if m[2] != "" {
+			schema.Description = "Pattern: " + m[2]
+		}
+
+		params = append(params, &openapi.Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+
+	return pathParamRegexp.ReplaceAllString(path, "{$1}"), params
+}
+
+func errorResponses(g *openapi.Generator, codes []errors.Code) map[string]*openapi.Response {
+	byStatus := make(map[int][]string)
+	for _, c := range append(codes, errors.CodeUnknown) {
+		byStatus[c.Status()] = append(byStatus[c.Status()], "`"+c.String()+"`")
+	}
+
+	res := make(map[string]*openapi.Response)
+	for status, list := range byStatus {
+		res[strconv.Itoa(status)] = &openapi.Response{
+			Description: fmt.Sprintf("%s. Error codes: %s", http.StatusText(status), strings.Join(list, ", ")),
+			Content: map[string]*openapi.MediaType{
+				"application/json": {Schema: g.Schema(&utils.ErrorResponse{})},
+			},
+		}
+	}
+
+	return res
+}
+
+func apiDescription() string {
+	var b strings.Builder
+	b.WriteString("A RESTful API for indexed Tezos data\n\n")
+	b.WriteString("# Errors\n\n")
+	b.WriteString("Errors are returned as JSON objects containing a message, an error code, invalid parameters if any and the request ID ")
+	b.WriteString("which is also sent in the `X-Request-ID` header.\n\n")
+	b.WriteString("| Code | HTTP status |\n|---|---|\n")
+	for _, c := range errors.Codes() {
+		fmt.Fprintf(&b, "| `%s` | %d |\n", c.String(), c.Status())
+	}
+	return b.String()
+}
+
+func newOpenAPIDocument(routes []*route) *openapi.Document {
+	doc := openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: &openapi.Info{
+			Title:       "Tezos Indexer API",
+			Description: apiDescription(),
+			Version:     "1.0.0",
+		},
+		Paths:      make(map[string]*openapi.PathItem),
+		Components: &openapi.Components{},
+	}
+	g := openapi.NewGenerator(doc.Components)
+
+	for _, r := range routes {
+		path, params := pathParameters(r.Path)
+		if r.Query != nil {
+			params = append(params, g.Parameters(r.Query)...)
+		}
+
+		op := openapi.Operation{
+			OperationID: r.operationID(),
+			Summary:     r.Summary,
+			Description: r.Description,
+			Tags:        r.Tags,
+			Parameters:  params,
+			Responses:   errorResponses(g, r.Errors),
+			Deprecated:  r.Deprecated,
+		}
+
+		if r.Body != nil {
+			op.RequestBody = &openapi.RequestBody{
+				Content:  map[string]*openapi.MediaType{"application/json": {Schema: g.Schema(r.Body)}},
+				Required: true,
+			}
+		}
+
+		status := r.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		ok := openapi.Response{Description: "Successful response"}
+		if len(r.Responses) != 0 {
+			var schema *openapi.Schema
+			if len(r.Responses) == 1 {
+				schema = g.Schema(r.Responses[0])
+			} else {
+				schema = &openapi.Schema{}
+				for _, v := range r.Responses {
+					schema.OneOf = append(schema.OneOf, g.Schema(v))
+				}
+			}
+			ok.Content = map[string]*openapi.MediaType{"application/json": {Schema: schema}}
+		} else if r.ContentType != "" {
+			ok.Content = map[string]*openapi.MediaType{r.ContentType: {}}
+		}
+		op.Responses[strconv.Itoa(status)] = &ok
+
+		item, exists := doc.Paths[path]
+		if !exists {
+			item = &openapi.PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(r.Method)] = &op
+	}
+
+	return &doc
+}
+
+func (s *Service) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	s.mtx.RLock()
+	doc := s.openAPI
+	s.mtx.RUnlock()
+
+	utils.JSONResponse(w, http.StatusOK, doc)
+}
+
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
+
+// serveRedoc serves the Redoc bundle from the local file set by redoc_file
+func (s *Service) serveRedoc(w http.ResponseWriter, r *http.Request) {
+	path := s.getConfig().RedocFile
+	if path == "" {
+		utils.JSONError(w, r, errRedocDisabled)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/javascript")
+	http.ServeFile(w, r, path)
+}
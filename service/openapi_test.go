@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestOpenAPIDocumentsAllRoutes(t *testing.T) {
+	s := &Service{}
+	c := &Config{}
+	if err := s.setAPIHandler(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// The specification as served to clients
+	rec := httptest.NewRecorder()
+	s.NewAPIHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("openapi.json: %d", rec.Code)
+	}
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	documented := func(path, method string) bool {
+		p, _ := pathParameters(path)
+		_, ok := doc.Paths[p][strings.ToLower(method)]
+		return ok
+	}
+
+	// Everything actually served must be documented
+	m, _ := s.newRouter(&Handler{}, c)
+	err := m.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			if !documented(path, method) {
+				t.Errorf("%s %s is registered but not documented", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]string)
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			if prev, ok := ids[op.OperationID]; ok {
+				t.Errorf("operation ID %q is used by both %s and %s %s", op.OperationID, prev, method, path)
+			}
+			ids[op.OperationID] = method + " " + path
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Keys which can't be changed without restart
+var restartKeys = map[string]bool{
+	"http_address":       true,
+	"grpc_address":       true,
+	"head_poll_interval": true,
+	"tls_cert_file":      true,
+	"tls_key_file":       true,
+	"tls_client_ca_file": true,
+	"tls_client_auth":    true,
+	"tls_check_interval": true,
+	"stats_interval":     true,
+	"stats_size":         true,
+	"fee_window":         true,
+	"labels_file":        true,
+}
+
+// configFields returns addressable fields of the configuration keyed by YAML keys
+func configFields(c *Config) map[string]reflect.Value {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	res := make(map[string]reflect.Value, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if key != "" && key != "-" {
+			res[key] = v.Field(i)
+		}
+	}
+	return res
+}
+
+// changedKeys returns sorted YAML keys of fields which differ between a and b
+func changedKeys(a, b *Config) []string {
+	fa, fb := configFields(a), configFields(b)
+
+	var res []string
+	for key, va := range fa {
+		if !reflect.DeepEqual(va.Interface(), fb[key].Interface()) {
+			res = append(res, key)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Reload applies the new configuration to the running service. Database pools are replaced if the cluster
+// configuration has been changed, the old ones are closed after all acquired connections are released.
+// Listen addresses, TLS settings, head polling, statistics update parameters, the fee window and the labels file
+// location require restart. The labels file itself is reread.
+// Certificate files themselves are reloaded automatically. The service has no rate limits or API keys, those are
+// expected to be enforced by a reverse proxy.
+func (s *Service) Reload(c *Config) error {
+	old := s.getConfig()
+
+	if s.labels != nil {
+		if err := s.labels.Load(); err != nil {
+			return err
+		}
+	}
+
+	changed := changedKeys(old, c)
+	if len(changed) == 0 {
+		s.log().Infoln("Configuration reloaded, nothing changed")
+		return nil
+	}
+
+	var applied, ignored []string
+	var dbChanged bool
+	for _, key := range changed {
+		if restartKeys[key] {
+			ignored = append(ignored, key)
+			continue
+		}
+		applied = append(applied, key)
+
+		switch key {
+		case "db_uri", "max_connections", "databases", "max_replica_lag", "health_check_interval":
+			dbChanged = true
+		}
+	}
+
+	if dbChanged {
+		cluster, err := newCluster(c, s.logger)
+		if err != nil {
+			return err
+		}
+
+		prev := s.setCluster(cluster, c)
+		// Pool.Close waits for acquired connections to be released
+		go prev.Close()
+	}
+
+	s.lru.Resize(c.CacheSize)
+	s.chain.setFinalityDepth(c.finalityDepth())
+
+	// Values not used on reload are kept as they were
+	oldFields, newFields := configFields(old), configFields(c)
+	for _, key := range ignored {
+		newFields[key].Set(oldFields[key])
+	}
+
+	s.mtx.Lock()
+	s.config = c
+	s.mtx.Unlock()
+
+	if err := s.setAPIHandler(c); err != nil {
+		return err
+	}
+
+	l := s.log().WithField("changed", strings.Join(applied, ","))
+	if len(ignored) != 0 {
+		l = l.WithField("restart_required", strings.Join(ignored, ","))
+	}
+	l.Infoln("Configuration reloaded")
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/labels"
+	"github.com/ecadlabs/tezos-indexer-api/middleware"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/gorilla/mux"
+)
+
+// route describes an API endpoint. All routes are registered from the table so each of them is documented.
+type route struct {
+	Name        string
+	Method      string
+	Path        string // gorilla/mux path template
+	Summary     string
+	Description string
+	Tags        []string
+	Query       interface{}   // Query string structure decoded using gorilla/schema
+	Body        interface{}   // JSON request body, optional
+	Status      int           // Success status, 200 by default
+	Responses   []interface{} // Possible response bodies, nil for non JSON endpoints
+	ContentType string        // Response content type, application/json by default
+	Errors      []errors.Code // Error codes returned by the endpoint besides errors.CodeUnknown
+	Handler     http.HandlerFunc
+	Deprecated  bool      // Responses carry the Deprecation header
+	Sunset      time.Time // Date after which a deprecated route is expected to be removed, optional
+	Successor   string    // Path template of the route replacing a deprecated one, optional
+}
+
+// operationID returns a unique route identifier. Name is shared by aliases of the same endpoint.
+func (r *route) operationID() string {
+	if r.Deprecated {
+		return "legacy_" + r.Name
+	}
+	return r.Name
+}
+
+// deprecation returns the middleware emitting deprecation headers of the route or nil
+func (r *route) deprecation() *middleware.Deprecation {
+	if !r.Deprecated {
+		return nil
+	}
+
+	d := middleware.Deprecation{Sunset: r.Sunset}
+	if r.Successor != "" {
+		successor := r.Successor
+		d.Successor = func(req *http.Request) string {
+			vars := mux.Vars(req)
+			return pathParamRegexp.ReplaceAllStringFunc(successor, func(p string) string {
+				return vars[pathParamRegexp.FindStringSubmatch(p)[1]]
+			})
+		}
+	}
+	return &d
+}
+
+// apiVersion is a group of routes sharing the path prefix
+type apiVersion struct {
+	Prefix string
+	Routes []*route
+}
+
+// apiRoutes returns routes of all API versions. Legacy unversioned paths are deprecated aliases of v1.
+func (s *Service) apiRoutes(h *Handler, c *Config) []*route {
+	v1 := &apiVersion{Prefix: "/v1", Routes: s.routesV1(h)}
+
+	var res []*route
+	for _, r := range v1.Routes {
+		vr := *r
+		vr.Path = v1.Prefix + r.Path
+		res = append(res, &vr)
+	}
+
+	for _, r := range v1.Routes {
+		lr := *r
+		lr.Deprecated = true
+		if lr.Sunset.IsZero() {
+			lr.Sunset = c.LegacySunset
+		}
+		lr.Successor = v1.Prefix + r.Path
+		res = append(res, &lr)
+	}
+
+	return append(res, s.serviceRoutes()...)
+}
+
+func (s *Service) routesV1(h *Handler) []*route {
+	return []*route{
+		{
+			Name:        "balances",
+			Method:      "GET",
+			Path:        "/balances/{pkh}",
+			Summary:     "Get balance history",
+			Description: "Returns balance updates of the contract in descending level order along with the resulting balance value.",
+			Tags:        []string{"balances"},
+			Query:       &getBalanceUpdateRequest{},
+			Responses:   []interface{}{&compactBalanceUpdate{}, []*storage.BalanceUpdate{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.GetBalanceUpdate,
+		},
+		{
+			Name:      "head",
+			Method:    "GET",
+			Path:      "/blocks/head",
+			Summary:   "Get chain head",
+			Tags:      []string{"blocks"},
+			Responses: []interface{}{&storage.Block{}},
+			Errors:    []errors.Code{errors.CodeResourceNotFound, errors.CodeQueryTimeout},
+			Handler:   h.GetHead,
+		},
+		{
+			Name:        "block",
+			Method:      "GET",
+			Path:        "/blocks/{level:[0-9]+}",
+			Summary:     "Get block",
+			Description: "Returns the canonical block at the given level.",
+			Tags:        []string{"blocks"},
+			Responses:   []interface{}{&storage.Block{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeQueryTimeout},
+			Handler:     h.GetBlock,
+		},
+		{
+			Name:        "reorgs",
+			Method:      "GET",
+			Path:        "/reorgs",
+			Summary:     "List forks",
+			Description: "Returns chain branches which don't lead to the current head.",
+			Tags:        []string{"blocks"},
+			Responses:   []interface{}{[]*storage.Fork{}},
+			Handler:     h.GetForks,
+		},
+		{
+			Name:        "cycles",
+			Method:      "GET",
+			Path:        "/cycles",
+			Summary:     "List cycles",
+			Description: "Returns summaries of the most recent cycles in descending order.",
+			Tags:        []string{"cycles"},
+			Query:       &getCyclesRequest{},
+			Responses:   []interface{}{[]*storage.Cycle{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.GetCycles,
+		},
+		{
+			Name:        "cycle",
+			Method:      "GET",
+			Path:        "/cycles/{cycle:[0-9]+}",
+			Summary:     "Get cycle",
+			Description: "Returns the cycle summary: level and time range, totals of fees, rewards and consumed gas, number of distinct bakers and the roll snapshot level.",
+			Tags:        []string{"cycles"},
+			Responses:   []interface{}{&storage.Cycle{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeQueryTimeout},
+			Handler:     h.GetCycle,
+		},
+		{
+			Name:        "voting_periods",
+			Method:      "GET",
+			Path:        "/voting/periods",
+			Summary:     "List voting periods",
+			Description: "Returns summaries of the most recent voting periods in descending order.",
+			Tags:        []string{"governance"},
+			Query:       &getVotingPeriodsRequest{},
+			Responses:   []interface{}{[]*storage.VotingPeriod{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.GetVotingPeriods,
+		},
+		{
+			Name:        "voting_period",
+			Method:      "GET",
+			Path:        "/voting/periods/{period:[0-9]+}",
+			Summary:     "Get voting period",
+			Description: "Returns the voting period kind, level range and proposals and ballot operations submitted during the period.",
+			Tags:        []string{"governance"},
+			Responses:   []interface{}{&storage.VotingPeriod{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeQueryTimeout},
+			Handler:     h.GetVotingPeriod,
+		},
+		{
+			Name:        "account",
+			Method:      "GET",
+			Path:        "/accounts/{address}",
+			Summary:     "Get account overview",
+			Description: "Returns the account type, current and frozen balances, manager, delegate, revealed public key, activation and deactivation levels, activity range and numbers of operations involving the account by kind.",
+			Tags:        []string{"accounts"},
+			Responses:   []interface{}{&storage.Account{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeQueryTimeout},
+			Handler:     h.GetAccount,
+		},
+		{
+			Name:        "account_originations",
+			Method:      "GET",
+			Path:        "/accounts/{address}/originations",
+			Summary:     "List originations",
+			Description: "Returns contracts originated by the account in descending level order along with their initial balances.",
+			Tags:        []string{"accounts"},
+			Query:       &getOriginationsRequest{},
+			Responses:   []interface{}{[]*storage.Origination{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.GetOriginations,
+		},
+		{
+			Name:        "contracts",
+			Method:      "GET",
+			Path:        "/contracts",
+			Summary:     "List contracts",
+			Description: "Returns originated (KT1) contracts managed by the given key in descending level order.",
+			Tags:        []string{"contracts"},
+			Query:       &getContractsRequest{},
+			Responses:   []interface{}{[]*storage.Contract{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.GetContracts,
+		},
+		{
+			Name:        "search",
+			Method:      "GET",
+			Path:        "/search",
+			Summary:     "Search",
+			Description: "Finds blocks, operations, implicit accounts and originated contracts by a level or an identifier prefix. The kind is recognized by the prefix. Exact matches come first.",
+			Tags:        []string{"search"},
+			Query:       &searchRequest{},
+			Responses:   []interface{}{[]*storage.SearchResult{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.Search,
+		},
+		{
+			Name:        "stats_richlist",
+			Method:      "GET",
+			Path:        "/stats/richlist",
+			Summary:     "Get rich list",
+			Description: "Returns accounts with the highest balance of the given kind. The list is precomputed periodically, the block it has been computed at is included.",
+			Tags:        []string{"stats"},
+			Query:       &getRichListRequest{},
+			Responses:   []interface{}{&richListResponse{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeNotReady},
+			Handler:     h.GetRichList,
+		},
+		{
+			Name:        "stats_volume",
+			Method:      "GET",
+			Path:        "/stats/volume",
+			Summary:     "Get top senders or receivers",
+			Description: "Returns accounts with the highest amount of transactions sent or received within the time window preceding the snapshot block. The list is precomputed periodically.",
+			Tags:        []string{"stats"},
+			Query:       &getVolumeRequest{},
+			Responses:   []interface{}{&volumeResponse{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeNotReady},
+			Handler:     h.GetVolume,
+		},
+		{
+			Name:        "stats_network",
+			Method:      "GET",
+			Path:        "/stats/network",
+			Summary:     "Get network statistics",
+			Description: "Returns transaction count, volume and fees, active and new accounts, originations and consumed gas per time bucket in descending order. The most recent bucket is incomplete unless the range end is set.",
+			Tags:        []string{"stats"},
+			Query:       &getNetworkStatsRequest{},
+			Responses:   []interface{}{[]*storage.NetworkStats{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeLimitTooBig, errors.CodeQueryTimeout},
+			Handler:     h.GetNetworkStats,
+		},
+		{
+			Name:        "stats_fees",
+			Method:      "GET",
+			Path:        "/stats/fees",
+			Summary:     "Get fee statistics",
+			Description: "Returns fee percentiles per operation kind over the recent blocks. The statistics are updated on each new chain head. Origination and delegation fees are known from balance updates only so free operations of these kinds are not counted.",
+			Tags:        []string{"stats"},
+			Responses:   []interface{}{&feeStatsResponse{}},
+			Errors:      []errors.Code{errors.CodeNotReady},
+			Handler:     h.GetFeeStats,
+		},
+		{
+			Name:        "stats_fee_suggestion",
+			Method:      "GET",
+			Path:        "/stats/fees/suggestion",
+			Summary:     "Get suggested fees",
+			Description: "Returns low, medium and high fee suggestions for an operation kind based on fees paid in the recent blocks. Returns `404` if no operation of the kind has been included in them.",
+			Tags:        []string{"stats"},
+			Query:       &getFeeSuggestionRequest{},
+			Responses:   []interface{}{&feeSuggestionResponse{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeNotReady},
+			Handler:     h.GetFeeSuggestion,
+		},
+		{
+			Name:        "labels",
+			Method:      "GET",
+			Path:        "/labels",
+			Summary:     "List address labels",
+			Description: "Returns address labels ordered by address. Labelled addresses found in other responses are accompanied by the `<field>_label` object.",
+			Tags:        []string{"labels"},
+			Query:       &getLabelsRequest{},
+			Responses:   []interface{}{[]*labels.Label{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeEndpointNotFound},
+			Handler:     h.GetLabels,
+		},
+		{
+			Name:        "label",
+			Method:      "GET",
+			Path:        "/labels/{address}",
+			Summary:     "Get address label",
+			Description: "Returns the label and tags of the address.",
+			Tags:        []string{"labels"},
+			Responses:   []interface{}{&labels.Label{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeEndpointNotFound},
+			Handler:     h.GetLabel,
+		},
+		{
+			Name:        "put_label",
+			Method:      "PUT",
+			Path:        "/labels/{address}",
+			Summary:     "Create or replace address label",
+			Description: "Requires a client certificate of one of the administrative identities. Returns 201 if the address had no label.",
+			Tags:        []string{"labels"},
+			Body:        &putLabelRequest{},
+			Responses:   []interface{}{&labels.Label{}},
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeUnauthorized, errors.CodeForbidden, errors.CodeEndpointNotFound},
+			Handler:     h.admin(h.PutLabel),
+		},
+		{
+			Name:        "delete_label",
+			Method:      "DELETE",
+			Path:        "/labels/{address}",
+			Summary:     "Delete address label",
+			Description: "Requires a client certificate of one of the administrative identities.",
+			Tags:        []string{"labels"},
+			Status:      http.StatusNoContent,
+			Errors:      []errors.Code{errors.CodeBadRequest, errors.CodeResourceNotFound, errors.CodeUnauthorized, errors.CodeForbidden, errors.CodeEndpointNotFound},
+			Handler:     h.admin(h.DeleteLabel),
+		},
+	}
+}
+
+// serviceRoutes returns unversioned service endpoints
+func (s *Service) serviceRoutes() []*route {
+	return []*route{
+		{
+			Name:        "status",
+			Method:      "GET",
+			Path:        "/status",
+			Summary:     "Get service status",
+			Description: "Returns the chain head seen by the service, database pools and cache statistics.",
+			Tags:        []string{"service"},
+			Responses:   []interface{}{map[string]interface{}{}},
+			Handler:     s.serveStatus,
+		},
+		{
+			Name:      "openapi",
+			Method:    "GET",
+			Path:      "/openapi.json",
+			Summary:   "Get OpenAPI specification",
+			Tags:      []string{"service"},
+			Responses: []interface{}{map[string]interface{}{}},
+			Handler:   s.serveOpenAPI,
+		},
+		{
+			Name:        "docs",
+			Method:      "GET",
+			Path:        "/docs",
+			Summary:     "API documentation",
+			Tags:        []string{"service"},
+			ContentType: "text/html",
+			Handler:     serveDocs,
+		},
+		{
+			Name:        "redoc",
+			Method:      "GET",
+			Path:        "/redoc.standalone.js",
+			Summary:     "Redoc bundle used by the API documentation",
+			Tags:        []string{"service"},
+			ContentType: "application/javascript",
+			Errors:      []errors.Code{errors.CodeEndpointNotFound},
+			Handler:     s.serveRedoc,
+		},
+	}
+}
+
+func registerRoutes(m *mux.Router, routes []*route) {
+	for _, r := range routes {
+		var h http.Handler = r.Handler
+		if d := r.deprecation(); d != nil {
+			h = d.Handler(h)
+		}
+		m.Methods(r.Method).Path(r.Path).Name(r.Name).Handler(h)
+	}
+}
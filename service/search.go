@@ -0,0 +1,138 @@
+package service
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 100
+	minSearchLength    = 4 // Shorter prefixes match too much
+)
+
+func validateAddress(s string) error {
+	_, err := tezos.ParseAddress(s)
+	return err
+}
+
+// searchKinds maps identifier prefixes to search kinds. Complete identifiers of the given length are validated.
+var searchKinds = []struct {
+	prefix   *tezos.Prefix
+	kind     string
+	length   int
+	validate func(string) error
+}{
+	{tezos.PrefixBlockHash, storage.SearchBlock, 51, tezos.ValidateBlockHash},
+	{tezos.PrefixOperationHash, storage.SearchOperation, 51, tezos.ValidateOperationHash},
+	{tezos.PrefixEd25519PublicKeyHash, storage.SearchAccount, 36, validateAddress},
+	{tezos.PrefixSecp256k1PublicKeyHash, storage.SearchAccount, 36, validateAddress},
+	{tezos.PrefixP256PublicKeyHash, storage.SearchAccount, 36, validateAddress},
+	{tezos.PrefixContractHash, storage.SearchContract, 36, validateAddress},
+}
+
+type searchRequest struct {
+	Query string `schema:"q,required" description:"Block level or a prefix of a block hash (B), operation hash (o) or address (tz1, tz2, tz3, KT1)"`
+	Limit int    `schema:"limit" description:"Maximum number of returned results (default 10)"`
+}
+
+func invalidSearch(reason string) error {
+	return errors.NewValidationError("Invalid query parameters", errors.CodeBadRequest, &errors.FieldError{
+		Field:  "q",
+		Reason: reason,
+	})
+}
+
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := searchRequest{
+		Limit: defaultSearchLimit,
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := checkLimit(req.Limit, maxSearchLimit); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	q := strings.TrimSpace(req.Query)
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	if level, err := strconv.ParseInt(q, 10, 64); err == nil {
+		block, err := h.Storage.GetBlock(ctx, level)
+		if err != nil && err != errors.ErrResourceNotFound {
+			utils.JSONError(w, r, err)
+			return
+		}
+
+		res := []*storage.SearchResult{}
+		if block != nil {
+			res = append(res, &storage.SearchResult{
+				Kind:  storage.SearchBlock,
+				ID:    block.Hash,
+				Level: &block.Level,
+				Exact: true,
+			})
+		}
+
+		utils.ConditionalJSONResponse(w, r, res, h.headValidator(), h.MaxAge)
+		return
+	}
+
+	if len(q) < minSearchLength {
+		utils.JSONError(w, r, invalidSearch("at least "+strconv.Itoa(minSearchLength)+" characters are required"))
+		return
+	}
+
+	if !tezos.IsBase58(q) {
+		utils.JSONError(w, r, invalidSearch("only digits or base58 characters are allowed"))
+		return
+	}
+
+	var kind string
+	for _, k := range searchKinds {
+		if strings.HasPrefix(q, k.prefix.Name) {
+			if len(q) >= k.length {
+				// A complete identifier can't be a prefix of anything else so report typos instead of returning nothing
+				if err := k.validate(q); err != nil {
+					utils.JSONError(w, r, invalidSearch(strings.TrimPrefix(err.Error(), "tezos: ")))
+					return
+				}
+			}
+			kind = k.kind
+			break
+		}
+	}
+
+	if kind == "" {
+		utils.JSONError(w, r, invalidSearch("unrecognized prefix, expected B, o, tz1, tz2, tz3 or KT1"))
+		return
+	}
+
+	res, err := h.Storage.SearchPrefix(ctx, kind, q, req.Limit)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	// Exact matches first
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Exact && !res[j].Exact
+	})
+
+	utils.ConditionalJSONResponse(w, r, res, h.headValidator(), h.MaxAge)
+}
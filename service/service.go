@@ -2,22 +2,58 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
+	"expvar"
+	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ecadlabs/tezos-indexer-api/cache"
 	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/labels"
 	"github.com/ecadlabs/tezos-indexer-api/middleware"
+	"github.com/ecadlabs/tezos-indexer-api/openapi"
+	"github.com/ecadlabs/tezos-indexer-api/rpc"
+	"github.com/ecadlabs/tezos-indexer-api/rpc/pb"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
 	"github.com/ecadlabs/tezos-indexer-api/storage/pg"
 	"github.com/ecadlabs/tezos-indexer-api/utils"
 	"github.com/gorilla/mux"
-	"github.com/jackc/pgx/v4/pgxpool"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultErrorDocsURL        = "/docs#section/Errors"
 )
 
 type Service struct {
-	pool    *pgxpool.Pool
-	storage *pg.PostgresStorage
-	config  *Config
-	logger  log.FieldLogger
+	pgStorage *pg.PostgresStorage
+	lru       *cache.LRU
+	storage   storage.Storage
+	chain     *chainMonitor
+	stats     *statsUpdater
+	fees      *feeTracker
+	labels    *labels.Store
+	metrics   *expvar.Map
+	logger    log.FieldLogger
+	ctx       context.Context
+	cancel    context.CancelFunc
+	api       atomic.Value // http.Handler
+
+	// Replaced on reload
+	mtx           sync.RWMutex
+	cluster       *pg.Cluster
+	clusterCancel context.CancelFunc
+	openAPI       *openapi.Document
+	config        *Config
 }
 
 func (s *Service) log() log.FieldLogger {
@@ -27,49 +63,329 @@ func (s *Service) log() log.FieldLogger {
 	return log.StandardLogger()
 }
 
+func newCluster(c *Config, logger log.FieldLogger) (cluster *pg.Cluster, err error) {
+	cluster = &pg.Cluster{
+		MaxLag: c.MaxReplicaLag,
+		Logger: logger,
+	}
+
+	var pools []*pg.Pool
+	defer func() {
+		if err != nil {
+			for _, p := range pools {
+				p.Pool.Close()
+			}
+		}
+	}()
+
+	for _, db := range c.databases() {
+		maxConns := db.MaxConnections
+		if maxConns == 0 {
+			maxConns = c.MaxConnections
+		}
+
+		pool, err := pg.NewPool(context.Background(), db.URI, db.Role, maxConns)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, pool)
+
+		switch db.Role {
+		case pg.RolePrimary, "":
+			if cluster.Primary != nil {
+				return nil, fmt.Errorf("only one primary database is allowed")
+			}
+			pool.Role = pg.RolePrimary
+			cluster.Primary = pool
+
+		case pg.RoleReplica:
+			cluster.Replicas = append(cluster.Replicas, pool)
+
+		default:
+			return nil, fmt.Errorf("unknown database role: %s", db.Role)
+		}
+	}
+
+	if cluster.Primary == nil {
+		return nil, fmt.Errorf("primary database is not configured")
+	}
+
+	if err := pg.CreateSchema(context.Background(), cluster); err != nil {
+		return nil, fmt.Errorf("creating database schema: %v", err)
+	}
+
+	return cluster, nil
+}
+
 func NewService(c *Config, logger log.FieldLogger) (*Service, error) {
-	poolConfig, err := pgxpool.ParseConfig(c.URI)
+	var labelStore *labels.Store
+	if c.LabelsFile != "" {
+		var err error
+		if labelStore, err = labels.NewStore(c.LabelsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	cluster, err := newCluster(c, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	if poolConfig.MaxConns == 0 {
-		poolConfig.MaxConns = int32(c.MaxConnections)
+	pgStorage := &pg.PostgresStorage{
+		DB:     cluster,
+		Logger: logger,
 	}
 
-	pool, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
-	if err != nil {
+	chain := &chainMonitor{
+		storage:   pgStorage,
+		canonical: pgStorage,
+		interval:  c.HeadPollInterval,
+		depth:     c.finalityDepth(),
+		logger:    logger,
+	}
+
+	// The cache is always present so it can be enabled on reload, handlers bypass it while it's disabled
+	lru := cache.NewLRU(c.CacheSize)
+	cs := &cache.Storage{
+		Storage: pgStorage,
+		Cache:   lru,
+	}
+	chain.onUpdate(func(u *chainUpdate) { cs.Update(u.Final, u.Reorg) })
+
+	stats := newStatsUpdater(pgStorage, chain, c.StatsInterval, c.StatsSize, logger)
+	chain.onUpdate(stats.onChainUpdate)
+
+	fees := newFeeTracker(pgStorage, chain, c.FeeWindow, logger)
+	chain.onUpdate(fees.onChainUpdate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Service{
+		pgStorage: pgStorage,
+		lru:       lru,
+		storage:   cs,
+		chain:     chain,
+		stats:     stats,
+		fees:      fees,
+		labels:    labelStore,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+		config:    c,
+	}
+
+	s.metrics = new(expvar.Map).Init()
+	s.metrics.Set("head", expvar.Func(func() interface{} { return chain.Head() }))
+	s.metrics.Set("final", expvar.Func(func() interface{} { return chain.Final() }))
+	s.metrics.Set("databases", expvar.Func(func() interface{} { return s.getCluster().Stats() }))
+	s.metrics.Set("cache", expvar.Func(func() interface{} { return cs.Stats() }))
+
+	s.setCluster(cluster, c)
+	if err := s.setAPIHandler(c); err != nil {
+		s.Close()
 		return nil, err
 	}
 
-	storage := &pg.PostgresStorage{DB: pool}
+	go chain.run(ctx)
+	go stats.run(ctx)
+	go fees.run(ctx)
 
-	return &Service{
-		pool:    pool,
-		storage: storage,
-		config:  c,
-		logger:  logger,
-	}, nil
+	return s, nil
 }
 
-func (s *Service) NewAPIHandler() http.Handler {
-	h := &Handler{
-		Storage: s.storage,
-		Logger:  s.logger,
-		Timeout: s.config.Timeout,
+func (c *Config) finalityDepth() int64 {
+	if c.FinalityDepth == 0 {
+		return defaultFinalityDepth
+	}
+	return int64(c.FinalityDepth)
+}
+
+func (s *Service) getConfig() *Config {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.config
+}
+
+func (s *Service) getCluster() *pg.Cluster {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.cluster
+}
+
+// setCluster starts the health check loop of the new cluster and stops the old one. Returns the replaced cluster.
+func (s *Service) setCluster(cluster *pg.Cluster, c *Config) *pg.Cluster {
+	ctx, cancel := context.WithCancel(s.ctx)
+	if len(cluster.Replicas) != 0 {
+		interval := c.HealthCheckInterval
+		if interval == 0 {
+			interval = defaultHealthCheckInterval
+		}
+		go cluster.Run(ctx, interval)
+	}
+
+	s.pgStorage.SetDB(cluster)
+
+	s.mtx.Lock()
+	old, oldCancel := s.cluster, s.clusterCancel
+	s.cluster, s.clusterCancel = cluster, cancel
+	s.mtx.Unlock()
+
+	if oldCancel != nil {
+		oldCancel()
 	}
+	return old
+}
+
+// Close stops background tasks and closes the database connection pool
+func (s *Service) Close() {
+	s.cancel()
+	s.getCluster().Close()
+}
 
+func (s *Service) serveStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write([]byte(s.metrics.String()))
+}
+
+// NewAPIHandler returns the HTTP API handler. The handler picks up configuration changes applied by Reload.
+func (s *Service) NewAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.api.Load().(http.Handler).ServeHTTP(w, r)
+	})
+}
+
+// newRouter returns the router serving all API routes. The routes are returned for the OpenAPI document.
+func (s *Service) newRouter(h *Handler, c *Config) (*mux.Router, []*route) {
 	m := mux.NewRouter()
-	if s.config.LogHTTP {
+	if c.LogHTTP {
 		m.Use((&middleware.Logging{}).Handler)
 	}
 	m.Use((&middleware.Recover{}).Handler)
 
-	m.Methods("GET").Path("/balances/{pkh}").HandlerFunc(h.GetBalanceUpdate)
+	routes := s.apiRoutes(h, c)
+	registerRoutes(m, routes)
 
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		utils.JSONError(w, errors.ErrResourceNotFound)
+		utils.JSONError(w, r, errors.ErrResourceNotFound)
+	})
+
+	return m, routes
+}
+
+func (s *Service) setAPIHandler(c *Config) error {
+	// Results are not marshalled for the cache if caching is disabled
+	var st storage.Storage = s.pgStorage
+	if c.CacheSize != 0 {
+		st = s.storage
+	}
+
+	h := &Handler{
+		Storage: st,
+		Chain:   s.chain,
+		Stats:   s.stats,
+		Fees:    s.fees,
+		Labels:  s.labels,
+		Logger:  s.logger,
+		Timeout: c.Timeout,
+		MaxAge:  c.CacheMaxAge,
+
+		QueryOptions: c.queryOptions(),
+
+		AdminIdentities: c.AdminIdentities,
+	}
+
+	m, routes := s.newRouter(h, c)
+
+	// Applied to all requests including unmatched ones
+	errOpt := utils.ErrorOptions{
+		Production: c.Production,
+		DocsURL:    c.ErrorDocsURL,
+	}
+	if errOpt.DocsURL == "" {
+		errOpt.DocsURL = defaultErrorDocsURL
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := utils.WithErrorOptions(r.Context(), &errOpt)
+		if s.labels != nil {
+			ctx = utils.WithEnricher(ctx, s.labels)
+		}
+		m.ServeHTTP(w, r.WithContext(ctx))
 	})
 
-	return m
+	s.mtx.Lock()
+	s.openAPI = newOpenAPIDocument(routes)
+	s.mtx.Unlock()
+
+	if len(c.CompressionEncodings) != 0 {
+		compress := middleware.Compress{
+			Encodings: c.CompressionEncodings,
+			Levels:    c.CompressionLevels,
+			MinSize:   c.CompressionMinSize,
+		}
+		if err := compress.Init(); err != nil {
+			return fmt.Errorf("compression: %v", err)
+		}
+		handler = compress.Handler(handler)
+	}
+
+	if len(c.CORSAllowedOrigins) != 0 {
+		cors := middleware.CORS{
+			AllowedOrigins:   c.CORSAllowedOrigins,
+			AllowedMethods:   c.CORSAllowedMethods,
+			AllowedHeaders:   c.CORSAllowedHeaders,
+			ExposedHeaders:   c.CORSExposedHeaders,
+			AllowCredentials: c.CORSAllowCredentials,
+			MaxAge:           c.CORSMaxAge,
+		}
+		handler = cors.Handler(handler)
+	}
+
+	handler = (&middleware.ClientCert{Identities: c.ClientIdentities}).Handler(handler)
+	s.api.Store(middleware.RequestID{}.Handler(handler))
+	return nil
+}
+
+// NewGRPCServer returns gRPC server with the indexer, health and reflection services registered. The TLS configuration
+// returned by Config.TLSConfig is shared with the HTTP server so client certificates are verified the same way.
+func (s *Service) NewGRPCServer(tlsConfig *tls.Config) *grpc.Server {
+	var opt []grpc.ServerOption
+	if tlsConfig != nil {
+		config := tlsConfig.Clone()
+		getConfig := config.GetConfigForClient
+		config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			c, err := getConfig(hello)
+			if err != nil || c == nil {
+				return c, err
+			}
+			// gRPC requires HTTP/2 to be negotiated
+			c = c.Clone()
+			c.NextProtos = []string{"h2"}
+			return c, nil
+		}
+		opt = append(opt, grpc.Creds(credentials.NewTLS(config)))
+	}
+	srv := grpc.NewServer(opt...)
+
+	// Streamed histories aren't cached
+	pb.RegisterIndexerServer(srv, &rpc.Server{
+		Storage: s.pgStorage,
+		Chain:   s.chain,
+		Logger:  s.logger,
+		Timeouts: func() rpc.Timeouts {
+			c := s.getConfig()
+			return rpc.Timeouts{
+				Unary:  c.Timeout,
+				Stream: c.GRPCStreamTimeout,
+			}
+		},
+	})
+
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+
+	reflection.Register(srv)
+
+	return srv
 }
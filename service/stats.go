@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStatsInterval = 10 * time.Minute
+	defaultStatsSize     = 100
+)
+
+// Rich list kinds
+const (
+	RichListSpendable = "spendable"
+	RichListDeposits  = "deposits"
+	RichListRewards   = "rewards"
+	RichListFees      = "fees"
+)
+
+var richListKinds = map[string]int{
+	RichListSpendable: storage.BalanceContract,
+	RichListDeposits:  storage.BalanceDeposits,
+	RichListRewards:   storage.BalanceRewards,
+	RichListFees:      storage.BalanceFees,
+}
+
+// Volume time windows counted back from the snapshot block timestamp
+var volumeWindows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// statsSnapshot contains rankings computed at the given block
+type statsSnapshot struct {
+	Level      int64
+	Hash       string
+	Timestamp  time.Time
+	ComputedAt time.Time
+	Size       int // Maximum number of entries per ranking
+
+	richList  map[string][]*storage.RichListEntry
+	senders   map[string][]*storage.VolumeEntry
+	receivers map[string][]*storage.VolumeEntry
+}
+
+// statsUpdater periodically recomputes rankings which are too expensive to be queried on request
+type statsUpdater struct {
+	storage  storage.StatsStorage
+	chain    chainState
+	interval time.Duration
+	size     int
+	logger   log.FieldLogger
+	notify   chan struct{}
+
+	mtx      sync.RWMutex
+	snapshot *statsSnapshot
+}
+
+func newStatsUpdater(s storage.StatsStorage, chain chainState, interval time.Duration, size int, logger log.FieldLogger) *statsUpdater {
+	if interval == 0 {
+		interval = defaultStatsInterval
+	}
+	if size == 0 {
+		size = defaultStatsSize
+	}
+	return &statsUpdater{
+		storage:  s,
+		chain:    chain,
+		interval: interval,
+		size:     size,
+		logger:   logger,
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+func (s *statsUpdater) log() log.FieldLogger {
+	if s.logger != nil {
+		return s.logger
+	}
+	return log.StandardLogger()
+}
+
+// Snapshot returns the most recent snapshot or nil if it's not computed yet
+func (s *statsUpdater) Snapshot() *statsSnapshot {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.snapshot
+}
+
+// onChainUpdate schedules the first update as soon as the chain head is known, later ones wait for the interval
+func (s *statsUpdater) onChainUpdate(*chainUpdate) {
+	if s.Snapshot() != nil {
+		return
+	}
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *statsUpdater) update(ctx context.Context) error {
+	head := s.chain.Head()
+	if head == nil {
+		return nil
+	}
+	if prev := s.Snapshot(); prev != nil && prev.Hash == head.Hash {
+		return nil
+	}
+
+	start := time.Now()
+	size := s.size
+
+	snap := statsSnapshot{
+		Level:     head.Level,
+		Hash:      head.Hash,
+		Timestamp: head.Timestamp,
+		Size:      size,
+		richList:  make(map[string][]*storage.RichListEntry, len(richListKinds)),
+		senders:   make(map[string][]*storage.VolumeEntry, len(volumeWindows)),
+		receivers: make(map[string][]*storage.VolumeEntry, len(volumeWindows)),
+	}
+
+	for name, kind := range richListKinds {
+		list, err := s.storage.GetRichList(ctx, kind, size)
+		if err != nil {
+			return err
+		}
+		snap.richList[name] = list
+	}
+
+	for name, d := range volumeWindows {
+		since := head.Timestamp.Add(-d)
+		sent, err := s.storage.GetTopVolume(ctx, since, false, size)
+		if err != nil {
+			return err
+		}
+		received, err := s.storage.GetTopVolume(ctx, since, true, size)
+		if err != nil {
+			return err
+		}
+		snap.senders[name], snap.receivers[name] = sent, received
+	}
+
+	snap.ComputedAt = time.Now()
+
+	s.mtx.Lock()
+	s.snapshot = &snap
+	s.mtx.Unlock()
+
+	s.log().WithFields(log.Fields{
+		"level":    snap.Level,
+		"duration": snap.ComputedAt.Sub(start),
+	}).Debugln("Statistics updated")
+
+	return nil
+}
+
+func (s *statsUpdater) run(ctx context.Context) {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+
+	for {
+		if err := s.update(ctx); err != nil && ctx.Err() == nil {
+			s.log().WithError(err).Errorln("Error updating statistics")
+		}
+
+		select {
+		case <-t.C:
+		case <-s.notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// statsSource provides precomputed rankings
+type statsSource interface {
+	Snapshot() *statsSnapshot
+}
+
+type statsResponse struct {
+	Level      int64     `json:"level"`
+	Hash       string    `json:"hash"`
+	Timestamp  time.Time `json:"timestamp"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+type richListResponse struct {
+	statsResponse
+	Kind     string                   `json:"kind"`
+	Accounts []*storage.RichListEntry `json:"accounts"`
+}
+
+type volumeResponse struct {
+	statsResponse
+	Window    string                 `json:"window"`
+	Direction string                 `json:"direction"`
+	Accounts  []*storage.VolumeEntry `json:"accounts"`
+}
+
+type getRichListRequest struct {
+	Kind  string `schema:"kind" description:"Balance kind: spendable, deposits, rewards or fees (default spendable)"`
+	Limit int    `schema:"limit" description:"Maximum number of returned accounts, up to stats_size (default all precomputed entries)"`
+}
+
+type getVolumeRequest struct {
+	Window    string `schema:"window" description:"Time window: day, week or month (default day)"`
+	Direction string `schema:"direction" description:"Rank by sent or received amount (default sent)"`
+	Limit     int    `schema:"limit" description:"Maximum number of returned accounts, up to stats_size (default all precomputed entries)"`
+}
+
+func (h *Handler) statsSnapshot() (*statsSnapshot, error) {
+	if h.Stats == nil {
+		return nil, errors.ErrNotReady
+	}
+	snap := h.Stats.Snapshot()
+	if snap == nil {
+		return nil, errors.ErrNotReady
+	}
+	return snap, nil
+}
+
+func snapshotResponse(snap *statsSnapshot) statsResponse {
+	return statsResponse{
+		Level:      snap.Level,
+		Hash:       snap.Hash,
+		Timestamp:  snap.Timestamp,
+		ComputedAt: snap.ComputedAt,
+	}
+}
+
+func snapshotValidator(snap *statsSnapshot) *utils.Validator {
+	return &utils.Validator{
+		Level:     snap.Level,
+		Hash:      snap.Hash,
+		Timestamp: snap.ComputedAt,
+	}
+}
+
+func (h *Handler) GetRichList(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getRichListRequest{
+		Kind: RichListSpendable,
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if _, ok := richListKinds[req.Kind]; !ok {
+		utils.JSONError(w, r, invalidParam("Invalid query parameters", "kind", fmt.Errorf("unknown balance kind %q", req.Kind)))
+		return
+	}
+
+	snap, err := h.statsSnapshot()
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := checkLimit(req.Limit, snap.Size); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	list := snap.richList[req.Kind]
+	if req.Limit > 0 && req.Limit < len(list) {
+		list = list[:req.Limit]
+	}
+
+	res := richListResponse{
+		statsResponse: snapshotResponse(snap),
+		Kind:          req.Kind,
+		Accounts:      list,
+	}
+
+	utils.ConditionalJSONResponse(w, r, &res, snapshotValidator(snap), h.MaxAge)
+}
+
+func (h *Handler) GetVolume(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getVolumeRequest{
+		Window:    "day",
+		Direction: "sent",
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if _, ok := volumeWindows[req.Window]; !ok {
+		utils.JSONError(w, r, invalidParam("Invalid query parameters", "window", fmt.Errorf("unknown time window %q", req.Window)))
+		return
+	}
+
+	if req.Direction != "sent" && req.Direction != "received" {
+		utils.JSONError(w, r, invalidParam("Invalid query parameters", "direction", fmt.Errorf("unknown direction %q", req.Direction)))
+		return
+	}
+
+	snap, err := h.statsSnapshot()
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := checkLimit(req.Limit, snap.Size); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	list := snap.senders[req.Window]
+	if req.Direction == "received" {
+		list = snap.receivers[req.Window]
+	}
+	if req.Limit > 0 && req.Limit < len(list) {
+		list = list[:req.Limit]
+	}
+
+	res := volumeResponse{
+		statsResponse: snapshotResponse(snap),
+		Window:        req.Window,
+		Direction:     req.Direction,
+		Accounts:      list,
+	}
+
+	utils.ConditionalJSONResponse(w, r, &res, snapshotValidator(snap), h.MaxAge)
+}
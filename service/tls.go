@@ -0,0 +1,183 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultTLSCheckInterval = 10 * time.Second
+
+// Client certificate verification modes
+const (
+	ClientAuthNone    = "none"
+	ClientAuthRequest = "request" // Verify if given
+	ClientAuthRequire = "require"
+)
+
+// tlsFiles keeps the server certificate and client CA bundle in sync with files on disk
+type tlsFiles struct {
+	certFile   string
+	keyFile    string
+	caFile     string
+	clientAuth tls.ClientAuthType
+	interval   time.Duration
+	logger     log.FieldLogger
+
+	mtx       sync.Mutex
+	config    *tls.Config
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func (t *tlsFiles) log() log.FieldLogger {
+	if t.logger != nil {
+		return t.logger
+	}
+	return log.StandardLogger()
+}
+
+func (t *tlsFiles) lastModified() (time.Time, error) {
+	var res time.Time
+	for _, name := range []string{t.certFile, t.keyFile, t.caFile} {
+		if name == "" {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(res) {
+			res = fi.ModTime()
+		}
+	}
+	return res, nil
+}
+
+func (t *tlsFiles) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.certFile, t.keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   t.clientAuth,
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if t.caFile != "" {
+		buf, err := ioutil.ReadFile(t.caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("%s: no certificates found", t.caFile)
+		}
+		config.ClientCAs = pool
+	}
+
+	return &config, nil
+}
+
+// get returns the current configuration reloading files if they have been modified.
+// The previous configuration is kept if reloading fails, e.g. in the middle of rotation.
+func (t *tlsFiles) get() (*tls.Config, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	now := time.Now()
+	if t.config != nil && now.Sub(t.checkedAt) < t.interval {
+		return t.config, nil
+	}
+	t.checkedAt = now
+
+	mt, err := t.lastModified()
+	if err == nil && t.config != nil && mt.Equal(t.modTime) {
+		return t.config, nil
+	}
+
+	var config *tls.Config
+	if err == nil {
+		config, err = t.load()
+	}
+
+	if err != nil {
+		if t.config == nil {
+			return nil, err
+		}
+		t.log().WithError(err).Errorln("Error reloading TLS certificates")
+		return t.config, nil
+	}
+
+	if t.config != nil {
+		t.log().Infoln("TLS certificates reloaded")
+	}
+	t.config, t.modTime = config, mt
+
+	return config, nil
+}
+
+// TLSConfig returns the HTTP server TLS configuration or nil if TLS is disabled.
+// Certificate files are reloaded automatically when modified.
+func (c *Config) TLSConfig(logger log.FieldLogger) (*tls.Config, error) {
+	if c.TLSCertFile == "" {
+		return nil, nil
+	}
+
+	files := tlsFiles{
+		certFile:   c.TLSCertFile,
+		keyFile:    c.TLSKeyFile,
+		caFile:     c.TLSClientCAFile,
+		clientAuth: c.clientAuth(),
+		interval:   c.TLSCheckInterval,
+		logger:     logger,
+	}
+	if files.interval == 0 {
+		files.interval = defaultTLSCheckInterval
+	}
+
+	// Fail early on startup
+	if _, err := files.get(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return files.get()
+		},
+		// Not used but required by http.Server.ServeTLS
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			config, err := files.get()
+			if err != nil {
+				return nil, err
+			}
+			return &config.Certificates[0], nil
+		},
+	}, nil
+}
+
+func (c *Config) clientAuth() tls.ClientAuthType {
+	switch c.TLSClientAuth {
+	case ClientAuthRequest:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	case ClientAuthNone:
+		return tls.NoClientCert
+	}
+	if c.TLSClientCAFile != "" {
+		return tls.RequireAndVerifyClientCert
+	}
+	return tls.NoClientCert
+}
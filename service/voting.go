@@ -0,0 +1,77 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultVotingPeriodsLimit = 10
+	maxVotingPeriodsLimit     = 100
+)
+
+type getVotingPeriodsRequest struct {
+	Before int64 `schema:"before" description:"Return periods preceding this one"`
+	Limit  int   `schema:"limit" description:"Maximum number of returned periods (default 10)"`
+}
+
+func (h *Handler) GetVotingPeriods(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	req := getVotingPeriodsRequest{
+		Before: -1,
+		Limit:  defaultVotingPeriodsLimit,
+	}
+
+	if err := decodeQuery(&req, r.Form); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	if err := checkLimit(req.Limit, maxVotingPeriodsLimit); err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	ret, err := h.Storage.GetVotingPeriods(ctx, req.Before, req.Limit)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	val := &utils.Validator{}
+	if len(ret) != 0 {
+		val = h.rangeValidator(ret[0].LastLevel, ret[0].LastHash)
+		val.Timestamp = ret[0].EndTime
+	}
+
+	utils.ConditionalJSONResponse(w, r, ret, val, h.MaxAge)
+}
+
+func (h *Handler) GetVotingPeriod(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.ParseInt(mux.Vars(r)["period"], 10, 64)
+	if err != nil {
+		utils.JSONError(w, r, invalidParam("Invalid path parameters", "period", err))
+		return
+	}
+
+	ctx, cancel := h.context(r)
+	defer cancel()
+
+	period, err := h.Storage.GetVotingPeriod(ctx, n)
+	if err != nil {
+		utils.JSONError(w, r, err)
+		return
+	}
+
+	val := h.rangeValidator(period.LastLevel, period.LastHash)
+	val.Timestamp = period.EndTime
+
+	utils.ConditionalJSONResponse(w, r, period, val, h.MaxAge)
+}
@@ -0,0 +1,69 @@
+package storage
+
+// Operation kinds, see operation_alpha.operation_kind
+const (
+	OperationEndorsement = iota
+	OperationSeedNonceRevelation
+	OperationDoubleEndorsementEvidence
+	OperationDoubleBakingEvidence
+	OperationActivateAccount
+	OperationProposals
+	OperationBallot
+	OperationReveal
+	OperationTransaction
+	OperationOrigination
+	OperationDelegation
+)
+
+var operationKinds = []string{
+	"endorsement",
+	"seed_nonce_revelation",
+	"double_endorsement_evidence",
+	"double_baking_evidence",
+	"activate_account",
+	"proposals",
+	"ballot",
+	"reveal",
+	"transaction",
+	"origination",
+	"delegation",
+}
+
+// OperationKindName returns the API name of the operation kind
+func OperationKindName(kind int) string {
+	if kind >= 0 && kind < len(operationKinds) {
+		return operationKinds[kind]
+	}
+	return "unknown"
+}
+
+// Voting period kinds, see block_alpha.voting_period_kind
+const (
+	VotingPeriodProposal = iota
+	VotingPeriodTestingVote
+	VotingPeriodTesting
+	VotingPeriodPromotionVote
+)
+
+var votingPeriodKinds = []string{
+	"proposal",
+	"testing_vote",
+	"testing",
+	"promotion_vote",
+}
+
+// VotingPeriodKindName returns the API name of the voting period kind
+func VotingPeriodKindName(kind int) string {
+	if kind >= 0 && kind < len(votingPeriodKinds) {
+		return votingPeriodKinds[kind]
+	}
+	return "unknown"
+}
+
+// Balance kinds, see balance.balance_kind
+const (
+	BalanceContract = iota
+	BalanceRewards
+	BalanceFees
+	BalanceDeposits
+)
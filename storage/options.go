@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// QueryOptions contains per request database tuning parameters
+type QueryOptions struct {
+	StatementTimeout time.Duration // Server side statement timeout
+	WorkMem          string        // Memory used by internal sort operations and hash tables, e.g. "64MB"
+}
+
+type queryOptionsKey struct{}
+
+// WithQueryOptions returns a copy of the context carrying query options
+func WithQueryOptions(ctx context.Context, opt *QueryOptions) context.Context {
+	return context.WithValue(ctx, queryOptionsKey{}, opt)
+}
+
+// GetQueryOptions returns query options carried by the context or nil
+func GetQueryOptions(ctx context.Context) *QueryOptions {
+	opt, _ := ctx.Value(queryOptionsKey{}).(*QueryOptions)
+	return opt
+}
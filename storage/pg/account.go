@@ -0,0 +1,144 @@
+package pg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+func (p *PostgresStorage) GetAccount(ctx context.Context, address string) (*storage.Account, error) {
+	a := storage.Account{
+		Address:    address,
+		Type:       storage.AccountImplicit,
+		Operations: make(map[string]int64),
+	}
+	if strings.HasPrefix(address, "KT1") {
+		a.Type = storage.AccountOriginated
+	}
+
+	contractQuery := `
+		SELECT
+			c.address IS NOT NULL,
+			COALESCE(c.mgr, ''),
+			COALESCE(c.delegate, ''),
+			i.pkh IS NOT NULL,
+			COALESCE(i.pk, ''),
+			(SELECT level FROM indexer_api.canonical_block WHERE hash = i.activated),
+			(SELECT level FROM indexer_api.canonical_block WHERE hash = i.revealed),
+			(
+				SELECT
+					MAX(cb.level)
+				FROM
+					deactivated AS d
+					JOIN indexer_api.canonical_block AS cb ON cb.hash = d.block_hash
+				WHERE
+					d.pkh = $1
+			)
+		FROM
+			(SELECT $1::varchar AS address) AS a
+			LEFT JOIN contract AS c ON c.address = a.address
+			LEFT JOIN implicit AS i ON i.pkh = a.address`
+
+	balanceQuery := `
+		SELECT
+			COALESCE(SUM(diff) FILTER (WHERE balance_kind = $2), 0),
+			COALESCE(SUM(diff) FILTER (WHERE balance_kind = $3), 0),
+			COALESCE(SUM(diff) FILTER (WHERE balance_kind = $4), 0),
+			COALESCE(SUM(diff) FILTER (WHERE balance_kind = $5), 0),
+			MIN(b.level),
+			MAX(b.level),
+			COALESCE((array_agg(b.hash ORDER BY b.level DESC))[1], '')
+		FROM
+			balance
+			JOIN block AS b ON b.hash = balance.block_hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = b.hash
+		WHERE
+			contract_address = $1`
+
+	// Operations in which the account is either a source or a target
+	opsQuery := `
+		SELECT
+			oa.operation_kind,
+			COUNT(*)
+		FROM
+			(
+				SELECT operation_hash, op_id FROM tx WHERE source = $1 OR destination = $1
+				UNION
+				SELECT operation_hash, op_id FROM origination WHERE source = $1 OR k = $1
+				UNION
+				SELECT operation_hash, op_id FROM delegation WHERE source = $1 OR pkh = $1
+			) AS ops
+			JOIN operation_alpha AS oa ON oa.hash = ops.operation_hash AND oa.id = ops.op_id
+			JOIN operation AS o ON o.hash = oa.hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = o.block_hash
+		GROUP BY
+			oa.operation_kind`
+
+	var (
+		query              string
+		isContract, isImpl bool
+	)
+
+	// All three queries must see the same data of the same replica
+	err := p.snapshot(ctx, func(q Queryer) error {
+		query = contractQuery
+		err := q.QueryRow(ctx, query, address).Scan(
+			&isContract,
+			&a.Manager,
+			&a.Delegate,
+			&isImpl,
+			&a.PublicKey,
+			&a.ActivatedLevel,
+			&a.RevealedLevel,
+			&a.DeactivatedLevel)
+		if err != nil || !isContract && !isImpl {
+			return err
+		}
+
+		query = balanceQuery
+		err = q.QueryRow(ctx, query, address,
+			storage.BalanceContract, storage.BalanceDeposits, storage.BalanceRewards, storage.BalanceFees).Scan(
+			&a.Balance,
+			&a.Frozen.Deposits,
+			&a.Frozen.Rewards,
+			&a.Frozen.Fees,
+			&a.FirstLevel,
+			&a.LastLevel,
+			&a.LastHash)
+		if err != nil {
+			return err
+		}
+
+		query = opsQuery
+		rows, err := q.Query(ctx, query, address)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				kind  int
+				count int64
+			)
+			if err := rows.Scan(&kind, &count); err != nil {
+				return err
+			}
+			a.Operations[storage.OperationKindName(kind)] = count
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	if !isContract && !isImpl {
+		return nil, errors.ErrResourceNotFound
+	}
+
+	return &a, nil
+}
@@ -0,0 +1,499 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
+	"github.com/jackc/pgx/v4"
+)
+
+// canonicalSchema creates the table of canonical blocks maintained by the API. Indexer tables are never modified.
+var canonicalSchema = []string{
+	`CREATE SCHEMA IF NOT EXISTS indexer_api`,
+	`CREATE TABLE IF NOT EXISTS indexer_api.canonical_block (
+		level int PRIMARY KEY,
+		hash char(51) NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS canonical_block_hash ON indexer_api.canonical_block (hash)`,
+}
+
+// canonicalColumns are copied or decoded from indexer tables by fillCanonical. The timestamp is set on every filled row.
+var canonicalColumns = []struct {
+	name string
+	typ  string
+}{
+	{"timestamp", "timestamp"},
+	{"consumed_gas", "numeric"}, // Decoded block_alpha.consumed_gas
+	{"voting_period", "int"},
+}
+
+var canonicalIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS canonical_block_timestamp ON indexer_api.canonical_block (timestamp)`,
+	`CREATE INDEX IF NOT EXISTS canonical_block_voting_period ON indexer_api.canonical_block (voting_period)`,
+	`CREATE INDEX IF NOT EXISTS canonical_block_pending ON indexer_api.canonical_block (level) WHERE timestamp IS NULL`,
+}
+
+// canonicalBatch is the number of blocks walked or filled per transaction
+const canonicalBatch = 10000
+
+// CreateSchema creates API owned tables on the primary database if they don't exist. Columns missing in tables created
+// by older versions are added and all rows are filled again on the next SetHead.
+func CreateSchema(ctx context.Context, db Beginner) error {
+	const (
+		columnQuery = `
+			SELECT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_schema = 'indexer_api' AND table_name = 'canonical_block' AND column_name = $1
+			)`
+		refillQuery = `UPDATE indexer_api.canonical_block SET timestamp = NULL`
+	)
+
+	tx, err := db.Begin(WithPrimary(ctx))
+	if err != nil {
+		return err
+	}
+
+	exec := func(q string, args ...interface{}) error {
+		if _, err := tx.Exec(ctx, q, args...); err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+		return nil
+	}
+
+	for _, q := range canonicalSchema {
+		if err := exec(q); err != nil {
+			return err
+		}
+	}
+
+	var added bool
+	for _, c := range canonicalColumns {
+		var exists bool
+		if err := tx.QueryRow(ctx, columnQuery, c.name).Scan(&exists); err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+		if !exists {
+			if err := exec(fmt.Sprintf("ALTER TABLE indexer_api.canonical_block ADD COLUMN %s %s", c.name, c.typ)); err != nil {
+				return err
+			}
+			added = true
+		}
+	}
+	if added {
+		if err := exec(refillQuery); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range canonicalIndexes {
+		if err := exec(q); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// inTx runs fn within a transaction on the primary database
+func (p *PostgresStorage) inTx(ctx context.Context, fn func(tx Tx) error) error {
+	b, ok := p.db().(Beginner)
+	if !ok {
+		return fmt.Errorf("pg: database doesn't support transactions")
+	}
+
+	tx, err := b.Begin(WithPrimary(ctx))
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetHead makes the chain ending with the head canonical. Queries return blocks of the canonical chain only so
+// the predecessors are walked from the head until the first block already known to be canonical.
+// Long walks (e.g. the first one) are committed in batches and resumed from the lowest canonical block if interrupted.
+func (p *PostgresStorage) SetHead(ctx context.Context, head *storage.Block) error {
+	const (
+		deleteQuery = `DELETE FROM indexer_api.canonical_block WHERE level > $1`
+		lowestQuery = `
+			SELECT
+				b.hash,
+				b.predecessor,
+				cb.level
+			FROM
+				indexer_api.canonical_block AS cb
+				JOIN block AS b ON b.hash = cb.hash
+			ORDER BY
+				cb.level
+			LIMIT 1`
+	)
+
+	// Canonical chain is written to the primary only
+	ctx = WithPrimary(ctx)
+
+	var (
+		from    = head.Hash
+		level   = head.Level
+		first   = true
+		resumed bool
+	)
+
+	for from != "" {
+		err := p.inTx(ctx, func(tx Tx) error {
+			if first {
+				// The head may go back after a reorganization
+				if _, err := tx.Exec(ctx, deleteQuery, head.Level); err != nil {
+					return p.wrapError(ctx, err, deleteQuery)
+				}
+			}
+
+			var err error
+			from, level, err = p.walkCanonical(ctx, tx, from, level)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		first = false
+
+		if from != "" {
+			p.log().WithField("level", level).Infoln("Walking the canonical chain")
+			continue
+		}
+
+		if !resumed {
+			// A previous walk may have been interrupted leaving levels below the lowest canonical block unset
+			resumed = true
+			var hash, predecessor string
+			err := p.db().QueryRow(ctx, lowestQuery).Scan(&hash, &predecessor, &level)
+			if err != nil && err != pgx.ErrNoRows {
+				return p.wrapError(ctx, err, lowestQuery)
+			}
+			if err == nil && predecessor != hash {
+				from, level = predecessor, level-1
+			}
+		}
+	}
+
+	return p.fillCanonical(ctx)
+}
+
+// walkCanonical walks up to canonicalBatch predecessors starting from the block and makes them canonical.
+// The block to continue from is returned unless the walk has reached a known canonical block or the first indexed one.
+func (p *PostgresStorage) walkCanonical(ctx context.Context, tx Tx, from string, level int64) (string, int64, error) {
+	const query = `
+		WITH RECURSIVE walk AS (
+			SELECT hash, level, predecessor FROM block WHERE hash = $1
+			UNION ALL
+			SELECT
+				b.hash, b.level, b.predecessor
+			FROM
+				block AS b
+				JOIN walk AS c ON b.hash = c.predecessor AND b.hash <> c.hash
+			WHERE
+				b.level > $2 AND
+				NOT EXISTS (SELECT 1 FROM indexer_api.canonical_block AS cb WHERE cb.level = c.level AND cb.hash = c.hash)
+		), inserted AS (
+			INSERT INTO indexer_api.canonical_block (level, hash)
+			SELECT
+				level, hash
+			FROM
+				walk
+			WHERE
+				NOT EXISTS (SELECT 1 FROM indexer_api.canonical_block AS cb WHERE cb.level = walk.level AND cb.hash = walk.hash)
+			ON CONFLICT (level) DO UPDATE SET hash = EXCLUDED.hash, timestamp = NULL, consumed_gas = NULL, voting_period = NULL
+		)
+		SELECT
+			walk.level,
+			walk.predecessor,
+			walk.predecessor = walk.hash OR EXISTS (SELECT 1 FROM indexer_api.canonical_block AS cb WHERE cb.level = walk.level AND cb.hash = walk.hash)
+		FROM
+			walk
+		ORDER BY
+			walk.level
+		LIMIT 1`
+
+	var (
+		lowest      int64
+		predecessor string
+		done        bool
+	)
+	err := tx.QueryRow(ctx, query, from, level-canonicalBatch).Scan(&lowest, &predecessor, &done)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", 0, nil
+		}
+		return "", 0, p.wrapError(ctx, err, query)
+	}
+
+	if done {
+		return "", 0, nil
+	}
+	return predecessor, lowest - 1, nil
+}
+
+// fillCanonical copies and decodes block data of canonical blocks so aggregates don't have to and filters can use indexes.
+// Each batch is committed separately.
+func (p *PostgresStorage) fillCanonical(ctx context.Context) error {
+	const (
+		selectQuery = `
+			SELECT
+				cb.level,
+				ba.consumed_gas
+			FROM
+				indexer_api.canonical_block AS cb
+				LEFT JOIN block_alpha AS ba ON ba.hash = cb.hash
+			WHERE
+				cb.timestamp IS NULL
+			ORDER BY
+				cb.level
+			LIMIT $1`
+		updateQuery = `
+			UPDATE
+				indexer_api.canonical_block AS cb
+			SET
+				timestamp = d.timestamp,
+				consumed_gas = d.gas,
+				voting_period = d.voting_period
+			FROM
+				(
+					SELECT
+						g.level,
+						b.timestamp,
+						NULLIF(g.gas, '')::numeric AS gas,
+						ba.voting_period
+					FROM
+						unnest($1::int[], $2::text[]) AS g(level, gas)
+						JOIN indexer_api.canonical_block AS c ON c.level = g.level
+						JOIN block AS b ON b.hash = c.hash
+						LEFT JOIN block_alpha AS ba ON ba.hash = c.hash
+				) AS d
+			WHERE
+				cb.level = d.level`
+	)
+
+	for filled := 0; ; {
+		var n int
+		err := p.inTx(ctx, func(tx Tx) error {
+			var (
+				levels []int32
+				gas    []string
+			)
+
+			rows, err := tx.Query(ctx, selectQuery, canonicalBatch)
+			if err != nil {
+				return p.wrapError(ctx, err, selectQuery)
+			}
+
+			for rows.Next() {
+				var (
+					level int32
+					hex   *string
+				)
+				if err := rows.Scan(&level, &hex); err != nil {
+					rows.Close()
+					return err
+				}
+
+				// Blocks without protocol specific data have no gas
+				var g string
+				if hex != nil {
+					v, err := tezos.DecodeUnsignedLE(*hex)
+					if err != nil {
+						// Zero is stored anyway so the block isn't picked up again
+						p.log().WithError(err).WithField("level", level).Warnln("Error decoding consumed gas")
+						v = new(big.Int)
+					}
+					g = v.String()
+				}
+
+				levels = append(levels, level)
+				gas = append(gas, g)
+			}
+			rows.Close()
+
+			if err := rows.Err(); err != nil {
+				return p.wrapError(ctx, err, selectQuery)
+			}
+
+			if n = len(levels); n == 0 {
+				return nil
+			}
+
+			if _, err := tx.Exec(ctx, updateQuery, levels, gas); err != nil {
+				return p.wrapError(ctx, err, updateQuery)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if n < canonicalBatch {
+			return nil
+		}
+
+		filled += n
+		p.log().WithField("blocks", filled).Infoln("Filling canonical block data")
+	}
+}
+
+// blockColumns are expected by getBlock
+const blockColumns = `
+			b.hash,
+			b.level,
+			b.predecessor,
+			b.timestamp,
+			b.fitness,
+			ba.consumed_gas`
+
+func (p *PostgresStorage) getBlock(ctx context.Context, query string, args ...interface{}) (*storage.Block, error) {
+	var (
+		b           storage.Block
+		fitness     string
+		consumedGas *string
+	)
+	err := p.query(ctx, func(q Queryer) error {
+		return q.QueryRow(ctx, query, args...).Scan(
+			&b.Hash,
+			&b.Level,
+			&b.Predecessor,
+			&b.Timestamp,
+			&fitness,
+			&consumedGas)
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrResourceNotFound
+		}
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	// Undecodable values are omitted rather than failing chain head polling
+	if b.Fitness, err = tezos.DecodeFitness(fitness); err != nil {
+		p.log().WithError(err).WithField("hash", b.Hash).Warnln("Error decoding block fitness")
+	}
+	if consumedGas != nil {
+		if b.ConsumedGas, err = tezos.DecodeUnsignedLE(*consumedGas); err != nil {
+			p.log().WithError(err).WithField("hash", b.Hash).Warnln("Error decoding consumed gas")
+		}
+	}
+
+	return &b, nil
+}
+
+func (p *PostgresStorage) GetHead(ctx context.Context) (*storage.Block, error) {
+	// Competing blocks at the same level are resolved by fitness which is ordered by length and then lexicographically
+	query := `
+		SELECT` + blockColumns + `
+		FROM
+			block AS b
+			LEFT JOIN block_alpha AS ba ON ba.hash = b.hash
+		ORDER BY
+			b.level DESC, length(b.fitness) DESC, b.fitness DESC
+		LIMIT 1`
+
+	return p.getBlock(ctx, query)
+}
+
+func (p *PostgresStorage) GetBlock(ctx context.Context, level int64) (*storage.Block, error) {
+	query := `
+		SELECT` + blockColumns + `
+		FROM
+			indexer_api.canonical_block AS cb
+			JOIN block AS b ON b.hash = cb.hash
+			LEFT JOIN block_alpha AS ba ON ba.hash = b.hash
+		WHERE
+			cb.level = $1`
+
+	return p.getBlock(ctx, query, level)
+}
+
+func (p *PostgresStorage) GetForks(ctx context.Context, head *storage.Block, minLevel int64) ([]*storage.Fork, error) {
+	// Walk the predecessor chain from the head down to minLevel only, everything below is considered final
+	query := `
+		WITH RECURSIVE canonical AS (
+			SELECT hash, level, predecessor FROM block WHERE hash = $1
+			UNION ALL
+			SELECT
+				b.hash, b.level, b.predecessor
+			FROM
+				block AS b
+				JOIN canonical AS c ON b.hash = c.predecessor AND b.hash <> c.hash
+			WHERE
+				b.level >= $2
+		)
+		SELECT
+			hash,
+			level,
+			predecessor,
+			timestamp
+		FROM
+			block
+		WHERE
+			level >= $2 AND hash NOT IN (SELECT hash FROM canonical)
+		ORDER BY
+			level`
+
+	var blocks []*storage.Block
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, head.Hash, minLevel)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var b storage.Block
+			if err := rows.Scan(&b.Hash, &b.Level, &b.Predecessor, &b.Timestamp); err != nil {
+				return err
+			}
+			blocks = append(blocks, &b)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	var (
+		forks []*storage.Fork
+		index = make(map[string]*storage.Fork)
+	)
+
+	for _, b := range blocks {
+		f, ok := index[b.Predecessor]
+		if !ok {
+			f = &storage.Fork{
+				AncestorLevel: b.Level - 1,
+				AncestorHash:  b.Predecessor,
+				FirstLevel:    b.Level,
+			}
+			forks = append(forks, f)
+		}
+
+		f.Blocks = append(f.Blocks, b)
+		f.LastLevel = b.Level
+		f.Depth = f.LastLevel - f.FirstLevel + 1
+		index[b.Hash] = f
+	}
+
+	return forks, nil
+}
+
+var _ storage.Storage = &PostgresStorage{}
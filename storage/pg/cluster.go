@@ -0,0 +1,306 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RolePrimary = "primary"
+	RoleReplica = "replica"
+)
+
+// DefaultMaxLag is used if the cluster maximum replication lag is not set
+const DefaultMaxLag = 30 * time.Second
+
+// Replica lag query. Lag is zero if all received WAL has been replayed, i.e. the primary is idle.
+const replicaLagQuery = `
+	SELECT
+		CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END`
+
+// Pool is a member of the database cluster
+type Pool struct {
+	Name string
+	Role string
+	Pool *pgxpool.Pool
+
+	mtx     sync.RWMutex
+	healthy bool
+	lag     time.Duration
+}
+
+func (p *Pool) setStatus(healthy bool, lag time.Duration) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.healthy, p.lag = healthy, lag
+}
+
+func (p *Pool) status() (bool, time.Duration) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.healthy, p.lag
+}
+
+// PoolStats contains per pool statistics
+type PoolStats struct {
+	Name          string  `json:"name"`
+	Role          string  `json:"role"`
+	Healthy       bool    `json:"healthy"`
+	LagSeconds    float64 `json:"lag_seconds"` // Replication lag, zero for the primary
+	AcquireCount  int64   `json:"acquire_count"`
+	AcquiredConns int32   `json:"acquired_conns"`
+	IdleConns     int32   `json:"idle_conns"`
+	TotalConns    int32   `json:"total_conns"`
+	MaxConns      int32   `json:"max_conns"`
+}
+
+// Cluster routes read only queries across healthy replicas and falls back to the primary
+type Cluster struct {
+	Primary  *Pool
+	Replicas []*Pool
+	MaxLag   time.Duration // Replicas lagging behind more than MaxLag are not used, DefaultMaxLag if zero
+	Logger   log.FieldLogger
+
+	next uint32
+}
+
+func (c *Cluster) log() log.FieldLogger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return log.StandardLogger()
+}
+
+func (c *Cluster) maxLag() time.Duration {
+	if c.MaxLag != 0 {
+		return c.MaxLag
+	}
+	return DefaultMaxLag
+}
+
+// NewPool connects to the database
+func NewPool(ctx context.Context, uri, role string, maxConns int) (*Pool, error) {
+	config, err := pgxpool.ParseConfig(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.MaxConns == 0 {
+		config.MaxConns = int32(maxConns)
+	}
+
+	pool, err := pgxpool.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pool{
+		Name:    fmt.Sprintf("%s:%d/%s", config.ConnConfig.Host, config.ConnConfig.Port, config.ConnConfig.Database),
+		Role:    role,
+		Pool:    pool,
+		healthy: true,
+	}, nil
+}
+
+// replica returns the next healthy replica or nil
+func (c *Cluster) replica() *Pool {
+	n := len(c.Replicas)
+	if n == 0 {
+		return nil
+	}
+
+	start := int(atomic.AddUint32(&c.next, 1))
+	for i := 0; i < n; i++ {
+		r := c.Replicas[(start+i)%n]
+		if healthy, _ := r.status(); healthy {
+			return r
+		}
+	}
+	return nil
+}
+
+// isConnError returns true if the error is not reported by the server and the query may be retried on another member
+func isConnError(err error) bool {
+	if err == nil || err == pgx.ErrNoRows || err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	_, ok := err.(*pgconn.PgError)
+	return !ok
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context which routes all cluster queries to the primary. Used where replication lag is not acceptable.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// replicaFor returns the replica to be used for the query or nil
+func (c *Cluster) replicaFor(ctx context.Context) *Pool {
+	if usePrimary(ctx) {
+		return nil
+	}
+	return c.replica()
+}
+
+func (c *Cluster) failed(p *Pool, err error) {
+	c.log().WithError(err).WithField("pool", p.Name).Warnln("Replica failed, falling back to primary")
+	p.setStatus(false, 0)
+}
+
+func (c *Cluster) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if r := c.replicaFor(ctx); r != nil {
+		rows, err := r.Pool.Query(ctx, sql, args...)
+		if !isConnError(err) {
+			return rows, err
+		}
+		c.failed(r, err)
+	}
+
+	return c.Primary.Pool.Query(ctx, sql, args...)
+}
+
+type clusterRow struct {
+	ctx     context.Context
+	cluster *Cluster
+	replica *Pool
+	row     pgx.Row
+	sql     string
+	args    []interface{}
+}
+
+func (r *clusterRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if r.replica != nil && isConnError(err) {
+		r.cluster.failed(r.replica, err)
+		return r.cluster.Primary.Pool.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	}
+	return err
+}
+
+func (c *Cluster) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if r := c.replicaFor(ctx); r != nil {
+		return &clusterRow{
+			ctx:     ctx,
+			cluster: c,
+			replica: r,
+			row:     r.Pool.QueryRow(ctx, sql, args...),
+			sql:     sql,
+			args:    args,
+		}
+	}
+
+	return c.Primary.Pool.QueryRow(ctx, sql, args...)
+}
+
+func (c *Cluster) Begin(ctx context.Context) (Tx, error) {
+	if r := c.replicaFor(ctx); r != nil {
+		tx, err := r.Pool.Begin(ctx, nil)
+		if err == nil {
+			return tx, nil
+		}
+		if !isConnError(err) {
+			return nil, err
+		}
+		c.failed(r, err)
+	}
+
+	tx, err := c.Primary.Pool.Begin(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// CheckHealth updates replicas status
+func (c *Cluster) CheckHealth(ctx context.Context) {
+	for _, r := range c.Replicas {
+		var lag float64
+		err := r.Pool.QueryRow(ctx, replicaLagQuery).Scan(&lag)
+		if err != nil {
+			if healthy, _ := r.status(); healthy {
+				c.log().WithError(err).WithField("pool", r.Name).Warnln("Replica health check failed")
+			}
+			r.setStatus(false, 0)
+			continue
+		}
+
+		d := time.Duration(lag * float64(time.Second))
+		healthy := d <= c.maxLag()
+		if wasHealthy, _ := r.status(); wasHealthy != healthy {
+			c.log().WithFields(log.Fields{
+				"pool":    r.Name,
+				"lag":     d,
+				"healthy": healthy,
+			}).Infoln("Replica status changed")
+		}
+		r.setStatus(healthy, d)
+	}
+}
+
+// Run checks replicas status periodically until the context is cancelled
+func (c *Cluster) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		c.CheckHealth(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stats returns per pool statistics
+func (c *Cluster) Stats() []*PoolStats {
+	pools := append([]*Pool{c.Primary}, c.Replicas...)
+	res := make([]*PoolStats, len(pools))
+
+	for i, p := range pools {
+		st := p.Pool.Stat()
+		healthy, lag := p.status()
+		res[i] = &PoolStats{
+			Name:          p.Name,
+			Role:          p.Role,
+			Healthy:       healthy,
+			LagSeconds:    lag.Seconds(),
+			AcquireCount:  st.AcquireCount(),
+			AcquiredConns: st.AcquiredConns(),
+			IdleConns:     st.IdleConns(),
+			TotalConns:    st.TotalConns(),
+			MaxConns:      st.MaxConns(),
+		}
+	}
+
+	return res
+}
+
+// Close closes all pools
+func (c *Cluster) Close() {
+	c.Primary.Pool.Close()
+	for _, r := range c.Replicas {
+		r.Pool.Close()
+	}
+}
+
+var _ Queryer = &Cluster{}
+var _ Beginner = &Cluster{}
@@ -0,0 +1,137 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+func (p *PostgresStorage) GetOriginations(ctx context.Context, source string, limit int) ([]*storage.Origination, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	query := `
+		SELECT
+			o.k,
+			o.operation_hash,
+			b.level,
+			b.hash,
+			b.timestamp,
+			COALESCE(c.credit, 0)
+		FROM
+			origination AS o
+			JOIN operation AS op ON op.hash = o.operation_hash
+			JOIN block AS b ON b.hash = op.block_hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = b.hash
+			LEFT JOIN contract AS c ON c.address = o.k
+		WHERE
+			o.source = $1
+		ORDER BY
+			b.level DESC, o.operation_hash, o.op_id
+		LIMIT $2`
+
+	var res []*storage.Origination
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, source, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.Origination{}
+
+		for rows.Next() {
+			var v storage.Origination
+			err = rows.Scan(
+				&v.Contract,
+				&v.OperationHash,
+				&v.Level,
+				&v.BlockHash,
+				&v.Timestamp,
+				&v.Credit)
+
+			if err != nil {
+				return err
+			}
+
+			res = append(res, &v)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
+
+func (p *PostgresStorage) GetContracts(ctx context.Context, manager string, limit int) ([]*storage.Contract, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	query := `
+		SELECT
+			c.address,
+			COALESCE(c.mgr, ''),
+			COALESCE(c.delegate, ''),
+			c.spendable,
+			c.delegatable,
+			COALESCE(c.credit, 0),
+			b.level,
+			b.hash,
+			b.timestamp
+		FROM
+			contract AS c
+			JOIN block AS b ON b.hash = c.block_hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = b.hash
+		WHERE
+			c.mgr = $1 AND c.address LIKE 'KT1%'
+		ORDER BY
+			b.level DESC, c.address
+		LIMIT $2`
+
+	var res []*storage.Contract
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, manager, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.Contract{}
+
+		for rows.Next() {
+			var v storage.Contract
+			err = rows.Scan(
+				&v.Address,
+				&v.Manager,
+				&v.Delegate,
+				&v.Spendable,
+				&v.Delegatable,
+				&v.Credit,
+				&v.Level,
+				&v.BlockHash,
+				&v.Timestamp)
+
+			if err != nil {
+				return err
+			}
+
+			res = append(res, &v)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,151 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// cyclesQuery summarizes canonical blocks of cycles matching the condition. $1 and $2 are fees and rewards balance kinds.
+const cyclesQuery = `
+	WITH blocks AS (
+		SELECT
+			ba.cycle,
+			b.level,
+			b.hash,
+			b.timestamp,
+			ba.baker,
+			cb.consumed_gas AS gas
+		FROM
+			block_alpha AS ba
+			JOIN block AS b ON b.hash = ba.hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = b.hash
+		WHERE
+			%s
+	), cycles AS (
+		SELECT
+			cycle,
+			MIN(level) AS first_level,
+			MAX(level) AS last_level,
+			(array_agg(hash ORDER BY level DESC))[1] AS last_hash,
+			MIN(timestamp) AS start_time,
+			MAX(timestamp) AS end_time,
+			COUNT(*) AS blocks,
+			COUNT(DISTINCT baker) AS bakers,
+			COALESCE(SUM(gas), 0) AS gas
+		FROM
+			blocks
+		GROUP BY
+			cycle
+	), frozen AS (
+		SELECT
+			blocks.cycle,
+			SUM(diff) FILTER (WHERE balance_kind = $1) AS fees,
+			SUM(diff) FILTER (WHERE balance_kind = $2) AS rewards
+		FROM
+			balance
+			JOIN blocks ON balance.block_hash = blocks.hash
+		WHERE
+			balance.balance_kind IN ($1, $2) AND balance.diff > 0
+		GROUP BY
+			blocks.cycle
+	)
+	SELECT
+		c.cycle,
+		c.first_level,
+		c.last_level,
+		c.last_hash,
+		c.start_time,
+		c.end_time,
+		c.blocks,
+		COALESCE(f.fees, 0),
+		COALESCE(f.rewards, 0),
+		c.gas::text,
+		c.bakers,
+		(SELECT MAX(level) FROM snapshot WHERE snapshot.cycle = c.cycle)
+	FROM
+		cycles AS c
+		LEFT JOIN frozen AS f ON f.cycle = c.cycle
+	ORDER BY
+		c.cycle DESC`
+
+func (p *PostgresStorage) getCycles(ctx context.Context, cond string, args ...interface{}) ([]*storage.Cycle, error) {
+	query := fmt.Sprintf(cyclesQuery, cond)
+	args = append([]interface{}{storage.BalanceFees, storage.BalanceRewards}, args...)
+
+	var res []*storage.Cycle
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.Cycle{}
+
+		for rows.Next() {
+			var (
+				c   storage.Cycle
+				gas string
+			)
+			err = rows.Scan(
+				&c.Cycle,
+				&c.FirstLevel,
+				&c.LastLevel,
+				&c.LastHash,
+				&c.StartTime,
+				&c.EndTime,
+				&c.Blocks,
+				&c.Fees,
+				&c.Rewards,
+				&gas,
+				&c.Bakers,
+				&c.SnapshotLevel)
+
+			if err != nil {
+				return err
+			}
+
+			if c.ConsumedGas, err = parseNumeric(gas); err != nil {
+				return err
+			}
+
+			res = append(res, &c)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
+
+func (p *PostgresStorage) GetCycles(ctx context.Context, before int64, limit int) ([]*storage.Cycle, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	if before < 0 {
+		return p.getCycles(ctx, "ba.cycle > (SELECT MAX(cycle) FROM block_alpha) - $3", limit)
+	}
+	return p.getCycles(ctx, "ba.cycle < $3 AND ba.cycle >= $3 - $4", before, limit)
+}
+
+func (p *PostgresStorage) GetCycle(ctx context.Context, cycle int64) (*storage.Cycle, error) {
+	res, err := p.getCycles(ctx, "ba.cycle = $3", cycle)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, errors.ErrResourceNotFound
+	}
+
+	return res[0], nil
+}
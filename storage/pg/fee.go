@@ -0,0 +1,104 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// GetBlockFees returns fees of transactions, originations and delegations. Transaction fees are taken from the tx table,
+// fees of other operations are known from frozen fee balance updates only so free operations of these kinds are not counted.
+func (p *PostgresStorage) GetBlockFees(ctx context.Context, from, to int64) ([]*storage.BlockFees, error) {
+	query := `
+		SELECT
+			b.level,
+			b.hash,
+			pb.hash,
+			f.kind,
+			COALESCE(array_agg(f.fee) FILTER (WHERE f.fee IS NOT NULL), '{}')
+		FROM
+			indexer_api.canonical_block AS b
+			LEFT JOIN indexer_api.canonical_block AS pb ON pb.level = b.level - 1
+			LEFT JOIN LATERAL (
+				SELECT
+					$3::smallint AS kind,
+					tx.fee
+				FROM
+					operation AS o
+					JOIN tx ON tx.operation_hash = o.hash
+				WHERE
+					o.block_hash = b.hash
+				UNION ALL
+				SELECT
+					oa.operation_kind,
+					bl.diff
+				FROM
+					balance AS bl
+					JOIN operation_alpha AS oa ON oa.hash = bl.operation_hash AND oa.id = bl.op_id
+				WHERE
+					bl.block_hash = b.hash AND bl.balance_kind = $4 AND bl.diff > 0 AND oa.operation_kind IN ($5, $6)
+			) AS f ON true
+		WHERE
+			b.level >= $1 AND b.level <= $2
+		GROUP BY
+			b.level, b.hash, pb.hash, f.kind
+		ORDER BY
+			b.level`
+
+	var res []*storage.BlockFees
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, from, to,
+			storage.OperationTransaction,
+			storage.BalanceFees,
+			storage.OperationOrigination,
+			storage.OperationDelegation)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.BlockFees{}
+
+		for rows.Next() {
+			var (
+				level       int64
+				hash        string
+				predecessor *string
+				kind        *int
+				fees        []int64
+			)
+			if err := rows.Scan(&level, &hash, &predecessor, &kind, &fees); err != nil {
+				return err
+			}
+
+			// One row per kind, blocks without fees have a single row with no kind
+			var b *storage.BlockFees
+			if len(res) != 0 && res[len(res)-1].Level == level {
+				b = res[len(res)-1]
+			} else {
+				b = &storage.BlockFees{
+					Level: level,
+					Hash:  hash,
+					Fees:  make(map[int][]int64),
+				}
+				if predecessor != nil {
+					b.Predecessor = *predecessor
+				}
+				res = append(res, b)
+			}
+
+			if kind != nil {
+				b.Fees[*kind] = fees
+			}
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,167 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// networkStatsQuery aggregates canonical blocks by date_trunc buckets using the indexed timestamp. Only the last buckets are joined with operations.
+const networkStatsQuery = `
+	WITH blocks AS (
+		SELECT
+			date_trunc($1::text, cb.timestamp) AS t,
+			cb.level,
+			cb.hash,
+			cb.consumed_gas AS gas
+		FROM
+			indexer_api.canonical_block AS cb
+		WHERE
+			cb.timestamp >= date_trunc($1::text, $2::timestamp) AND cb.timestamp < $3
+	), buckets AS (
+		SELECT
+			t,
+			MIN(level) AS first_level,
+			MAX(level) AS last_level,
+			(array_agg(hash ORDER BY level DESC))[1] AS last_hash,
+			COUNT(*) AS blocks,
+			COALESCE(SUM(gas), 0) AS gas
+		FROM
+			blocks
+		GROUP BY
+			t
+		ORDER BY
+			t DESC
+		LIMIT $4
+	), txs AS (
+		SELECT
+			blocks.t,
+			tx.source,
+			tx.destination,
+			tx.amount,
+			tx.fee
+		FROM
+			tx
+			JOIN operation AS o ON o.hash = tx.operation_hash
+			JOIN blocks ON blocks.hash = o.block_hash
+			JOIN buckets ON buckets.t = blocks.t
+	), tx_stats AS (
+		SELECT
+			t,
+			COUNT(*) AS n,
+			SUM(amount) AS volume,
+			SUM(fee) AS fees
+		FROM
+			txs
+		GROUP BY
+			t
+	), active AS (
+		SELECT
+			t,
+			COUNT(DISTINCT address) AS n
+		FROM
+			(SELECT t, source AS address FROM txs UNION SELECT t, destination FROM txs) AS a
+		GROUP BY
+			t
+	), new_accounts AS (
+		-- Separate joins so both implicit.activated and implicit.revealed indexes can be used
+		SELECT
+			t,
+			COUNT(*) AS n
+		FROM
+			(
+				SELECT blocks.t FROM implicit AS i JOIN blocks ON blocks.hash = i.activated
+				UNION ALL
+				SELECT blocks.t FROM implicit AS i JOIN blocks ON blocks.hash = i.revealed WHERE i.activated IS NULL
+			) AS a
+		GROUP BY
+			t
+	), originations AS (
+		SELECT
+			blocks.t,
+			COUNT(*) AS n
+		FROM
+			origination AS og
+			JOIN operation AS o ON o.hash = og.operation_hash
+			JOIN blocks ON blocks.hash = o.block_hash
+		GROUP BY
+			blocks.t
+	)
+	SELECT
+		bk.t,
+		bk.first_level,
+		bk.last_level,
+		bk.last_hash,
+		bk.blocks,
+		COALESCE(tx.n, 0),
+		COALESCE(tx.volume, 0),
+		COALESCE(tx.fees, 0),
+		COALESCE(a.n, 0),
+		COALESCE(na.n, 0),
+		COALESCE(og.n, 0),
+		bk.gas::text
+	FROM
+		buckets AS bk
+		LEFT JOIN tx_stats AS tx ON tx.t = bk.t
+		LEFT JOIN active AS a ON a.t = bk.t
+		LEFT JOIN new_accounts AS na ON na.t = bk.t
+		LEFT JOIN originations AS og ON og.t = bk.t
+	ORDER BY
+		bk.t DESC`
+
+func (p *PostgresStorage) GetNetworkStats(ctx context.Context, interval string, start, end time.Time, limit int) ([]*storage.NetworkStats, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	var res []*storage.NetworkStats
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, networkStatsQuery, interval, start, end, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.NetworkStats{}
+
+		for rows.Next() {
+			var (
+				s   storage.NetworkStats
+				gas string
+			)
+			err = rows.Scan(
+				&s.Timestamp,
+				&s.FirstLevel,
+				&s.LastLevel,
+				&s.LastHash,
+				&s.Blocks,
+				&s.Transactions,
+				&s.Volume,
+				&s.Fees,
+				&s.ActiveAccounts,
+				&s.NewAccounts,
+				&s.Originations,
+				&gas)
+
+			if err != nil {
+				return err
+			}
+
+			if s.ConsumedGas, err = parseNumeric(gas); err != nil {
+				return err
+			}
+
+			res = append(res, &s)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, networkStatsQuery)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// accountOperationsQuery selects manager operations where the account is either a source or a target
+const accountOperationsQuery = `
+	WITH ops AS (
+		SELECT operation_hash, op_id, source, destination AS target, amount, fee, parameters FROM tx WHERE source = $1 OR destination = $1
+		UNION ALL
+		SELECT operation_hash, op_id, source, k, NULL, NULL, NULL FROM origination WHERE source = $1 OR k = $1
+		UNION ALL
+		SELECT operation_hash, op_id, source, pkh, NULL, NULL, NULL FROM delegation WHERE source = $1 OR pkh = $1
+	)
+	SELECT
+		ops.operation_hash,
+		ops.op_id,
+		oa.operation_kind,
+		b.level,
+		b.hash,
+		b.timestamp,
+		ops.source,
+		COALESCE(ops.target, ''),
+		COALESCE(ops.amount, 0),
+		COALESCE(ops.fee, 0),
+		COALESCE(ops.parameters, '')
+	FROM
+		ops
+		JOIN operation_alpha AS oa ON oa.hash = ops.operation_hash AND oa.id = ops.op_id
+		JOIN operation AS o ON o.hash = ops.operation_hash
+		JOIN block AS b ON b.hash = o.block_hash
+		JOIN indexer_api.canonical_block AS cb ON cb.hash = b.hash
+	WHERE
+		TRUE`
+
+func (p *PostgresStorage) EachOperation(ctx context.Context, address string, start, end time.Time, limit int, fn func(*storage.AccountOperation) error) error {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	query := accountOperationsQuery
+	arg := []interface{}{address}
+	idx := 2
+
+	if !start.IsZero() {
+		query += fmt.Sprintf(" AND b.timestamp >= $%d", idx)
+		arg = append(arg, start)
+		idx++
+	}
+
+	if !end.IsZero() {
+		query += fmt.Sprintf(" AND b.timestamp < $%d", idx)
+		arg = append(arg, end)
+		idx++
+	}
+
+	query += fmt.Sprintf(" ORDER BY b.level DESC, ops.operation_hash, ops.op_id LIMIT $%d", idx)
+	arg = append(arg, limit)
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, arg...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				v    storage.AccountOperation
+				kind int
+			)
+			err = rows.Scan(
+				&v.Hash,
+				&v.ID,
+				&kind,
+				&v.Level,
+				&v.BlockHash,
+				&v.Timestamp,
+				&v.Source,
+				&v.Destination,
+				&v.Amount,
+				&v.Fee,
+				&v.Parameters)
+
+			if err != nil {
+				return err
+			}
+
+			v.Kind = storage.OperationKindName(kind)
+			if err := fn(&v); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+
+	return p.wrapError(ctx, err, query)
+}
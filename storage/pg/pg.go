@@ -3,10 +3,13 @@ package pg
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ecadlabs/tezos-indexer-api/storage"
 	"github.com/jackc/pgx/v4"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -18,8 +21,33 @@ type Queryer interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 }
 
+// parseNumeric parses an arbitrary-precision integer selected as text
+func parseNumeric(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("pg: invalid integer %q", s)
+	}
+	return v, nil
+}
+
 type PostgresStorage struct {
-	DB Queryer
+	DB     Queryer // Use SetDB to replace it while the storage is in use
+	Logger log.FieldLogger
+
+	mtx sync.RWMutex
+}
+
+// SetDB replaces the database connection
+func (p *PostgresStorage) SetDB(db Queryer) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.DB = db
+}
+
+func (p *PostgresStorage) db() Queryer {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.DB
 }
 
 func (p *PostgresStorage) GetBalanceUpdate(ctx context.Context, address string, start, end time.Time, limit int) ([]*storage.BalanceUpdate, error) {
@@ -27,15 +55,35 @@ func (p *PostgresStorage) GetBalanceUpdate(ctx context.Context, address string,
 		limit = defaultLimit
 	}
 
+	res := make([]*storage.BalanceUpdate, 0, limit)
+	err := p.EachBalanceUpdate(ctx, address, start, end, limit, func(v *storage.BalanceUpdate) error {
+		res = append(res, v)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (p *PostgresStorage) EachBalanceUpdate(ctx context.Context, address string, start, end time.Time, limit int, fn func(*storage.BalanceUpdate) error) error {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
 	query := `
 		SELECT
-		    level,
-			timestamp,
+			block.level,
+			block.timestamp,
 			diff::numeric,
-			SUM(diff::numeric) OVER (ORDER BY level) AS value
+			SUM(diff::numeric) OVER (ORDER BY block.level) AS value,
+			block.hash
 		FROM
-			balance 
-			JOIN block ON balance.block_hash = block.hash 
+			balance
+			JOIN block ON balance.block_hash = block.hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = block.hash
 		WHERE
 			contract_address = $1
 		`
@@ -49,7 +97,7 @@ func (p *PostgresStorage) GetBalanceUpdate(ctx context.Context, address string,
 		idx++
 	}
 
-	query += " ORDER BY level DESC"
+	query += " ORDER BY block.level DESC"
 
 	if !start.IsZero() {
 		// WHERE condition affects the window frame and the computation of the integral balance value so use a subquery
@@ -62,32 +110,33 @@ func (p *PostgresStorage) GetBalanceUpdate(ctx context.Context, address string,
 	arg = append(arg, limit)
 	idx++
 
-	rows, err := p.DB.Query(ctx, query, arg...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	res := make([]*storage.BalanceUpdate, 0, limit)
-
-	for rows.Next() {
-		var v storage.BalanceUpdate
-		err = rows.Scan(
-			&v.BlockLevel,
-			&v.BlockTimestamp,
-			&v.Diff,
-			&v.Value)
-
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, arg...)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var v storage.BalanceUpdate
+			err = rows.Scan(
+				&v.BlockLevel,
+				&v.BlockTimestamp,
+				&v.Diff,
+				&v.Value,
+				&v.BlockHash)
+
+			if err != nil {
+				return err
+			}
+
+			if err := fn(&v); err != nil {
+				return err
+			}
 		}
 
-		res = append(res, &v)
-	}
-
-	if rows.Err() != nil {
-		return nil, err
-	}
+		return rows.Err()
+	})
 
-	return res, nil
+	return p.wrapError(ctx, err, query)
 }
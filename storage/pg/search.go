@@ -0,0 +1,103 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// Prefix search uses LIKE which can be served by an index only with pattern operators or C collation,
+// e.g. CREATE INDEX ON block (hash bpchar_pattern_ops)
+var searchQueries = map[string]string{
+	storage.SearchBlock: `
+		SELECT
+			hash,
+			level
+		FROM
+			indexer_api.canonical_block
+		WHERE
+			hash LIKE $1
+		ORDER BY
+			hash
+		LIMIT $2`,
+
+	storage.SearchOperation: `
+		SELECT
+			o.hash,
+			b.level
+		FROM
+			operation AS o
+			JOIN indexer_api.canonical_block AS b ON b.hash = o.block_hash
+		WHERE
+			o.hash LIKE $1
+		ORDER BY
+			o.hash
+		LIMIT $2`,
+
+	storage.SearchAccount: `
+		SELECT
+			pkh,
+			NULL::int
+		FROM
+			implicit
+		WHERE
+			pkh LIKE $1
+		ORDER BY
+			pkh
+		LIMIT $2`,
+
+	storage.SearchContract: `
+		SELECT
+			c.address,
+			b.level
+		FROM
+			contract AS c
+			JOIN indexer_api.canonical_block AS b ON b.hash = c.block_hash
+		WHERE
+			c.address LIKE $1
+		ORDER BY
+			c.address
+		LIMIT $2`,
+}
+
+func (p *PostgresStorage) SearchPrefix(ctx context.Context, kind, prefix string, limit int) ([]*storage.SearchResult, error) {
+	query, ok := searchQueries[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown search kind: %s", kind)
+	}
+
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	var res []*storage.SearchResult
+
+	err := p.query(ctx, func(q Queryer) error {
+		// The prefix is expected to be validated by the caller and contain no wildcards
+		rows, err := q.Query(ctx, query, prefix+"%", limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.SearchResult{}
+
+		for rows.Next() {
+			v := storage.SearchResult{Kind: kind}
+			if err := rows.Scan(&v.ID, &v.Level); err != nil {
+				return err
+			}
+			v.Exact = v.ID == prefix
+			res = append(res, &v)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,115 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+func (p *PostgresStorage) GetRichList(ctx context.Context, kind int, limit int) ([]*storage.RichListEntry, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	query := `
+		SELECT
+			contract_address,
+			SUM(diff) AS balance
+		FROM
+			balance
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = balance.block_hash
+		WHERE
+			balance_kind = $1
+		GROUP BY
+			contract_address
+		ORDER BY
+			balance DESC, contract_address
+		LIMIT $2`
+
+	var res []*storage.RichListEntry
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, kind, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.RichListEntry{}
+
+		for rows.Next() {
+			var v storage.RichListEntry
+			if err := rows.Scan(&v.Address, &v.Balance); err != nil {
+				return err
+			}
+			res = append(res, &v)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
+
+func (p *PostgresStorage) GetTopVolume(ctx context.Context, since time.Time, received bool, limit int) ([]*storage.VolumeEntry, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	column := "source"
+	if received {
+		column = "destination"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			tx.%s,
+			SUM(tx.amount) AS volume,
+			COUNT(*)
+		FROM
+			tx
+			JOIN operation AS o ON o.hash = tx.operation_hash
+			JOIN block AS b ON b.hash = o.block_hash
+			JOIN indexer_api.canonical_block AS cb ON cb.hash = b.hash
+		WHERE
+			b.timestamp >= $1
+		GROUP BY
+			tx.%[1]s
+		ORDER BY
+			volume DESC, tx.%[1]s
+		LIMIT $2`, column)
+
+	var res []*storage.VolumeEntry
+
+	err := p.query(ctx, func(q Queryer) error {
+		rows, err := q.Query(ctx, query, since, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res = []*storage.VolumeEntry{}
+
+		for rows.Next() {
+			var v storage.VolumeEntry
+			if err := rows.Scan(&v.Address, &v.Volume, &v.Count); err != nil {
+				return err
+			}
+			res = append(res, &v)
+		}
+
+		return rows.Err()
+	})
+
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,121 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+	"github.com/ecadlabs/tezos-indexer-api/utils"
+	"github.com/jackc/pgconn"
+	log "github.com/sirupsen/logrus"
+)
+
+// Tx is a database transaction
+type Tx interface {
+	Queryer
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+// Beginner is implemented by Queryers capable of starting a transaction
+type Beginner interface {
+	Begin(ctx context.Context) (Tx, error)
+}
+
+const pgErrQueryCanceled = "57014"
+
+var spaceRegexp = regexp.MustCompile(`\s+`)
+
+func normalizeQuery(query string) string {
+	return strings.TrimSpace(spaceRegexp.ReplaceAllString(query, " "))
+}
+
+func (p *PostgresStorage) log() log.FieldLogger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return log.StandardLogger()
+}
+
+// wrapError converts statement timeouts and cancellations into errors.CodeQueryTimeout
+func (p *PostgresStorage) wrapError(ctx context.Context, err error, query string) error {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*pgconn.PgError)
+	if ok && e.Code == pgErrQueryCanceled || ctx.Err() == context.DeadlineExceeded {
+		l := p.log().WithError(err).WithField("query", normalizeQuery(query))
+		if id := utils.RequestID(ctx); id != "" {
+			l = l.WithField("request_id", id)
+		}
+		l.Warnln("Query timed out")
+		return errors.Wrap(err, errors.CodeQueryTimeout)
+	}
+
+	return err
+}
+
+// query runs fn within a transaction if query options are set in the context
+func (p *PostgresStorage) query(ctx context.Context, fn func(q Queryer) error) error {
+	return p.transact(ctx, false, fn)
+}
+
+// snapshot runs fn within a read only transaction so all its queries see the same data of the same database
+func (p *PostgresStorage) snapshot(ctx context.Context, fn func(q Queryer) error) error {
+	return p.transact(ctx, true, fn)
+}
+
+func (p *PostgresStorage) transact(ctx context.Context, snapshot bool, fn func(q Queryer) error) error {
+	opt := storage.GetQueryOptions(ctx)
+	db := p.db()
+	b, ok := db.(Beginner)
+	if !ok || !snapshot && (opt == nil || opt.StatementTimeout == 0 && opt.WorkMem == "") {
+		return fn(db)
+	}
+
+	tx, err := b.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if snapshot {
+		if _, err := tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"); err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+	}
+
+	if opt == nil {
+		opt = &storage.QueryOptions{}
+	}
+
+	// set_config(..., true) is equivalent to SET LOCAL but accepts parameters
+	if opt.StatementTimeout != 0 {
+		// Round up as zero disables the timeout
+		ms := fmt.Sprintf("%d", (opt.StatementTimeout+time.Millisecond-1)/time.Millisecond)
+		if _, err := tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", ms); err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+	}
+
+	if opt.WorkMem != "" {
+		if _, err := tx.Exec(ctx, "SELECT set_config('work_mem', $1, true)", opt.WorkMem); err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,202 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecadlabs/tezos-indexer-api/errors"
+	"github.com/ecadlabs/tezos-indexer-api/storage"
+)
+
+// votingPeriodsQuery summarizes canonical blocks of voting periods matching the condition. $1 and $2 are proposals and ballot operation kinds.
+const votingPeriodsQuery = `
+	WITH blocks AS (
+		SELECT
+			cb.voting_period,
+			ba.voting_period_kind,
+			cb.level,
+			cb.hash,
+			cb.timestamp
+		FROM
+			indexer_api.canonical_block AS cb
+			JOIN block_alpha AS ba ON ba.hash = cb.hash
+		WHERE
+			%s
+	), periods AS (
+		SELECT
+			voting_period,
+			(array_agg(voting_period_kind ORDER BY level DESC))[1] AS kind,
+			MIN(level) AS first_level,
+			MAX(level) AS last_level,
+			(array_agg(hash ORDER BY level DESC))[1] AS last_hash,
+			MIN(timestamp) AS start_time,
+			MAX(timestamp) AS end_time
+		FROM
+			blocks
+		GROUP BY
+			voting_period
+	), ops AS (
+		SELECT
+			blocks.voting_period,
+			COUNT(*) FILTER (WHERE oa.operation_kind = $1) AS proposals,
+			COUNT(*) FILTER (WHERE oa.operation_kind = $2) AS ballots
+		FROM
+			operation_alpha AS oa
+			JOIN operation AS o ON o.hash = oa.hash
+			JOIN blocks ON blocks.hash = o.block_hash
+		WHERE
+			oa.operation_kind IN ($1, $2)
+		GROUP BY
+			blocks.voting_period
+	)
+	SELECT
+		p.voting_period,
+		p.kind,
+		p.first_level,
+		p.last_level,
+		p.last_hash,
+		p.start_time,
+		p.end_time,
+		COALESCE(ops.proposals, 0),
+		COALESCE(ops.ballots, 0)
+	FROM
+		periods AS p
+		LEFT JOIN ops ON ops.voting_period = p.voting_period
+	ORDER BY
+		p.voting_period DESC`
+
+func (p *PostgresStorage) getVotingPeriods(ctx context.Context, q Queryer, cond string, args ...interface{}) ([]*storage.VotingPeriod, error) {
+	query := fmt.Sprintf(votingPeriodsQuery, cond)
+	args = append([]interface{}{storage.OperationProposals, storage.OperationBallot}, args...)
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+	defer rows.Close()
+
+	res := []*storage.VotingPeriod{}
+
+	for rows.Next() {
+		var (
+			v    storage.VotingPeriod
+			kind int
+		)
+		err = rows.Scan(
+			&v.Period,
+			&kind,
+			&v.FirstLevel,
+			&v.LastLevel,
+			&v.LastHash,
+			&v.StartTime,
+			&v.EndTime,
+			&v.Proposals,
+			&v.Ballots)
+
+		if err != nil {
+			return nil, err
+		}
+
+		v.Kind = storage.VotingPeriodKindName(kind)
+		res = append(res, &v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, p.wrapError(ctx, err, query)
+	}
+
+	return res, nil
+}
+
+func (p *PostgresStorage) GetVotingPeriods(ctx context.Context, before int64, limit int) ([]*storage.VotingPeriod, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	cond, args := "cb.voting_period > (SELECT MAX(voting_period) FROM indexer_api.canonical_block) - $3", []interface{}{limit}
+	if before >= 0 {
+		cond, args = "cb.voting_period < $3 AND cb.voting_period >= $3 - $4", []interface{}{before, limit}
+	}
+
+	var res []*storage.VotingPeriod
+	err := p.query(ctx, func(q Queryer) (err error) {
+		res, err = p.getVotingPeriods(ctx, q, cond, args...)
+		return err
+	})
+	return res, err
+}
+
+// votingOperationsQuery returns proposals and ballots of the voting period
+const votingOperationsQuery = `
+	SELECT
+		oa.hash,
+		oa.id,
+		oa.operation_kind,
+		cb.level,
+		cb.hash,
+		cb.timestamp
+	FROM
+		indexer_api.canonical_block AS cb
+		JOIN operation AS o ON o.block_hash = cb.hash
+		JOIN operation_alpha AS oa ON oa.hash = o.hash
+	WHERE
+		cb.voting_period = $1 AND oa.operation_kind IN ($2, $3)
+	ORDER BY
+		cb.level, oa.hash, oa.id`
+
+func (p *PostgresStorage) GetVotingPeriod(ctx context.Context, period int64) (*storage.VotingPeriod, error) {
+	var v *storage.VotingPeriod
+
+	// The summary and the operations list must agree
+	err := p.snapshot(ctx, func(q Queryer) error {
+		res, err := p.getVotingPeriods(ctx, q, "cb.voting_period = $3", period)
+		if err != nil {
+			return err
+		}
+
+		if len(res) == 0 {
+			return errors.ErrResourceNotFound
+		}
+		v = res[0]
+
+		rows, err := q.Query(ctx, votingOperationsQuery, period, storage.OperationProposals, storage.OperationBallot)
+		if err != nil {
+			return p.wrapError(ctx, err, votingOperationsQuery)
+		}
+		defer rows.Close()
+
+		v.Operations = []*storage.VotingOperation{}
+
+		for rows.Next() {
+			var (
+				op   storage.VotingOperation
+				kind int
+			)
+			err = rows.Scan(
+				&op.Hash,
+				&op.ID,
+				&kind,
+				&op.Level,
+				&op.BlockHash,
+				&op.Timestamp)
+
+			if err != nil {
+				return err
+			}
+
+			op.Kind = storage.OperationKindName(kind)
+			v.Operations = append(v.Operations, &op)
+		}
+
+		if err := rows.Err(); err != nil {
+			return p.wrapError(ctx, err, votingOperationsQuery)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
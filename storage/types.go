@@ -2,7 +2,10 @@ package storage
 
 import (
 	"context"
+	"math/big"
 	"time"
+
+	"github.com/ecadlabs/tezos-indexer-api/tezos"
 )
 
 /*
@@ -96,20 +99,6 @@ type OperationAlpha struct {
 	OperationKind int
 }
 
-const (
-	OperationEndorsement = iota
-	OperationSeedNonceRevelation
-	OperationDoubleEndorsementEvidence
-	OperationDoubleBakingEvidence
-	OperationActivateAccount
-	OperationProposals
-	OperationBallot
-	OperationReveal
-	OperationTransaction
-	OperationOrigination
-	OperationDelegation
-)
-
 // Implicit accounts (including deactivated ones)
 type Implicit struct {
 	PKH string // b58-encoded public key hash: tz1/tz1/tz3...
@@ -132,13 +121,6 @@ type Endorsement struct {
 	Slot      int
 }
 
-const (
-	VotingPeriodProposal = iota
-	VotingPeriodTestingVote
-	VotingPeriodTesting
-	VotingPeriodPromotionVote
-)
-
 // Deactivated accounts
 type Deactivated struct {
 	PKH       string // PKH of the deactivated account (tz1...)
@@ -210,13 +192,6 @@ type Balance struct {
 	Diff int64
 }
 
-const (
-	BalanceContract = iota
-	BalanceRewards
-	BalanceFees
-	BalanceDeposits
-)
-
 // Snapshots
 // the snapshot block for a given cycle is obtained as follows
 // at the last block of cycle n, the snapshot block for cycle n+6 is selected
@@ -243,11 +218,290 @@ type DelegatedContract struct {
 // API specific types
 type BalanceUpdate struct {
 	BlockLevel     int64     `json:"level"`
+	BlockHash      string    `json:"block_hash"`
 	BlockTimestamp time.Time `json:"timestamp"`
 	Diff           int64     `json:"diff"`
 	Value          int64     `json:"value"`
 }
 
 type BalanceStorage interface {
+	// GetBalanceUpdate returns balance updates of the given address in descending level order
 	GetBalanceUpdate(ctx context.Context, address string, start, end time.Time, limit int) ([]*BalanceUpdate, error)
+	// EachBalanceUpdate calls fn for each balance update in the same order as GetBalanceUpdate while reading them from the database.
+	// An error returned by fn stops the iteration and is returned.
+	EachBalanceUpdate(ctx context.Context, address string, start, end time.Time, limit int, fn func(*BalanceUpdate) error) error
+}
+
+type Block struct {
+	Hash        string         `json:"hash"`
+	Level       int64          `json:"level"`
+	Predecessor string         `json:"predecessor"`
+	Timestamp   time.Time      `json:"timestamp"`
+	Fitness     *tezos.Fitness `json:"fitness,omitempty"`
+	ConsumedGas *big.Int       `json:"consumed_gas,omitempty"` // Missing if the block has no protocol specific data
+}
+
+// Fork is a chain branch which doesn't lead to the current head
+type Fork struct {
+	AncestorLevel int64    `json:"ancestor_level"` // Level of the last common block
+	AncestorHash  string   `json:"ancestor_hash"`
+	FirstLevel    int64    `json:"first_level"` // First affected level
+	LastLevel     int64    `json:"last_level"`  // Last affected level
+	Depth         int64    `json:"depth"`       // Number of orphaned levels
+	Blocks        []*Block `json:"blocks"`      // Orphaned blocks
+}
+
+type ChainStorage interface {
+	// GetHead returns the highest block known to the indexer
+	GetHead(ctx context.Context) (*Block, error)
+	// GetBlock returns the canonical block at the given level
+	GetBlock(ctx context.Context, level int64) (*Block, error)
+	// GetForks returns branches above minLevel which don't lead to the given head
+	GetForks(ctx context.Context, head *Block, minLevel int64) ([]*Fork, error)
+}
+
+// Cycle contains cycle summary
+type Cycle struct {
+	Cycle         int64     `json:"cycle"`
+	FirstLevel    int64     `json:"first_level"`
+	LastLevel     int64     `json:"last_level"`
+	LastHash      string    `json:"last_hash"` // Hash of the last indexed block of the cycle
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Blocks        int64     `json:"blocks"`
+	Fees          int64     `json:"fees"`    // Total fees collected by bakers
+	Rewards       int64     `json:"rewards"` // Total baking and endorsement rewards
+	ConsumedGas   *big.Int  `json:"consumed_gas"`
+	Bakers        int64     `json:"bakers"`         // Number of distinct bakers
+	SnapshotLevel *int64    `json:"snapshot_level"` // Roll snapshot level used for the cycle rights, if selected
+}
+
+type CycleStorage interface {
+	// GetCycles returns up to limit cycles preceding the given one in descending order. All cycles up to the head are considered if before is negative.
+	GetCycles(ctx context.Context, before int64, limit int) ([]*Cycle, error)
+	// GetCycle returns the cycle summary
+	GetCycle(ctx context.Context, cycle int64) (*Cycle, error)
+}
+
+// VotingOperation is a proposals or ballot operation
+type VotingOperation struct {
+	Hash      string    `json:"hash"`
+	ID        int       `json:"id"`   // Index in the operation contents list
+	Kind      string    `json:"kind"` // "proposals" or "ballot"
+	Level     int64     `json:"level"`
+	BlockHash string    `json:"block_hash"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// VotingPeriod contains voting period summary
+type VotingPeriod struct {
+	Period     int64              `json:"period"`
+	Kind       string             `json:"kind"` // "proposal", "testing_vote", "testing" or "promotion_vote"
+	FirstLevel int64              `json:"first_level"`
+	LastLevel  int64              `json:"last_level"`
+	LastHash   string             `json:"last_hash"` // Hash of the last indexed block of the period
+	StartTime  time.Time          `json:"start_time"`
+	EndTime    time.Time          `json:"end_time"`
+	Proposals  int64              `json:"proposals"` // Number of proposals operations
+	Ballots    int64              `json:"ballots"`   // Number of ballot operations
+	Operations []*VotingOperation `json:"operations,omitempty"`
+}
+
+type VotingStorage interface {
+	// GetVotingPeriods returns up to limit voting periods preceding the given one in descending order. All periods up to the head are considered if before is negative.
+	GetVotingPeriods(ctx context.Context, before int64, limit int) ([]*VotingPeriod, error)
+	// GetVotingPeriod returns the voting period summary along with proposals and ballot operations
+	GetVotingPeriod(ctx context.Context, period int64) (*VotingPeriod, error)
+}
+
+// FrozenBalance contains baker's frozen balances
+type FrozenBalance struct {
+	Deposits int64 `json:"deposits"`
+	Rewards  int64 `json:"rewards"`
+	Fees     int64 `json:"fees"`
+}
+
+// Account aggregates everything known about the address
+type Account struct {
+	Address          string           `json:"address"`
+	Type             string           `json:"type"` // "implicit" or "originated"
+	Balance          int64            `json:"balance"`
+	Frozen           FrozenBalance    `json:"frozen"`
+	Manager          string           `json:"manager,omitempty"`
+	Delegate         string           `json:"delegate,omitempty"`
+	PublicKey        string           `json:"public_key,omitempty"`
+	ActivatedLevel   *int64           `json:"activated_level,omitempty"`
+	RevealedLevel    *int64           `json:"revealed_level,omitempty"`
+	DeactivatedLevel *int64           `json:"deactivated_level,omitempty"` // Level of the last baker deactivation
+	FirstLevel       *int64           `json:"first_level,omitempty"`       // First balance update level
+	LastLevel        *int64           `json:"last_level,omitempty"`        // Last balance update level
+	LastHash         string           `json:"last_hash,omitempty"`         // Hash of the last balance update block
+	Operations       map[string]int64 `json:"operations"`                  // Number of operations involving the account by kind
+}
+
+// Account types
+const (
+	AccountImplicit   = "implicit"
+	AccountOriginated = "originated"
+)
+
+type AccountStorage interface {
+	// GetAccount returns the account overview
+	GetAccount(ctx context.Context, address string) (*Account, error)
+}
+
+// AccountOperation is a transaction, origination or delegation involving an account
+type AccountOperation struct {
+	Hash        string    `json:"hash"`
+	ID          int       `json:"id"`   // Index in the operation contents list
+	Kind        string    `json:"kind"` // "transaction", "origination" or "delegation"
+	Level       int64     `json:"level"`
+	BlockHash   string    `json:"block_hash"`
+	Timestamp   time.Time `json:"timestamp"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination,omitempty"` // Transaction destination, originated contract or the new delegate
+	Amount      int64     `json:"amount,omitempty"`      // Transactions only
+	Fee         int64     `json:"fee,omitempty"`         // Transactions only
+	Parameters  string    `json:"parameters,omitempty"`  // JSON encoded Micheline transaction parameters
+}
+
+type OperationStorage interface {
+	// EachOperation calls fn for each transaction, origination and delegation involving the account in descending level order
+	// while reading them from the database. An error returned by fn stops the iteration and is returned.
+	EachOperation(ctx context.Context, address string, start, end time.Time, limit int, fn func(*AccountOperation) error) error
+}
+
+// Origination describes a contract originated by the account
+type Origination struct {
+	Contract      string    `json:"contract"`
+	OperationHash string    `json:"operation_hash"`
+	Level         int64     `json:"level"`
+	BlockHash     string    `json:"block_hash"`
+	Timestamp     time.Time `json:"timestamp"`
+	Credit        int64     `json:"credit"` // Initial balance
+}
+
+// Contract describes an originated contract
+type Contract struct {
+	Address     string    `json:"address"`
+	Manager     string    `json:"manager,omitempty"`
+	Delegate    string    `json:"delegate,omitempty"`
+	Spendable   bool      `json:"spendable"`
+	Delegatable bool      `json:"delegatable"`
+	Credit      int64     `json:"credit"` // Initial balance
+	Level       int64     `json:"level"`  // Origination level
+	BlockHash   string    `json:"block_hash"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type ContractStorage interface {
+	// GetOriginations returns contracts originated by the account in descending level order
+	GetOriginations(ctx context.Context, source string, limit int) ([]*Origination, error)
+	// GetContracts returns originated contracts managed by the key in descending level order
+	GetContracts(ctx context.Context, manager string, limit int) ([]*Contract, error)
+}
+
+// Search result kinds
+const (
+	SearchBlock     = "block"
+	SearchOperation = "operation"
+	SearchAccount   = "account"  // Implicit account
+	SearchContract  = "contract" // Originated contract
+)
+
+// SearchResult is an object found by its identifier
+type SearchResult struct {
+	Kind  string `json:"kind"`
+	ID    string `json:"id"`              // Hash or address
+	Level *int64 `json:"level,omitempty"` // Block level, operation inclusion or contract origination level
+	Exact bool   `json:"exact"`
+}
+
+type SearchStorage interface {
+	// SearchPrefix returns objects of the given kind which identifiers start with the prefix in ascending order
+	SearchPrefix(ctx context.Context, kind, prefix string, limit int) ([]*SearchResult, error)
+}
+
+// RichListEntry is an account balance of a specific kind
+type RichListEntry struct {
+	Address string `json:"address"`
+	Balance int64  `json:"balance"`
+}
+
+// VolumeEntry contains total amount of transactions sent or received by the account
+type VolumeEntry struct {
+	Address string `json:"address"`
+	Volume  int64  `json:"volume"`
+	Count   int64  `json:"count"` // Number of transactions
+}
+
+type StatsStorage interface {
+	// GetRichList returns accounts with the highest balance of the given kind (see Balance* constants)
+	GetRichList(ctx context.Context, kind int, limit int) ([]*RichListEntry, error)
+	// GetTopVolume returns accounts with the highest volume of transactions sent (or received) since the given time
+	GetTopVolume(ctx context.Context, since time.Time, received bool, limit int) ([]*VolumeEntry, error)
+}
+
+// NetworkStats summarizes canonical blocks of a time bucket
+type NetworkStats struct {
+	Timestamp      time.Time `json:"timestamp"` // Start of the bucket
+	FirstLevel     int64     `json:"first_level"`
+	LastLevel      int64     `json:"last_level"`
+	LastHash       string    `json:"last_hash"`
+	Blocks         int64     `json:"blocks"`
+	Transactions   int64     `json:"transactions"`
+	Volume         int64     `json:"volume"`          // Total amount transferred
+	Fees           int64     `json:"fees"`            // Total transaction fees
+	ActiveAccounts int64     `json:"active_accounts"` // Number of distinct transaction senders and receivers
+	NewAccounts    int64     `json:"new_accounts"`    // Number of activated or revealed implicit accounts
+	Originations   int64     `json:"originations"`
+	ConsumedGas    *big.Int  `json:"consumed_gas"`
+}
+
+// Network statistics bucket sizes
+const (
+	IntervalHour  = "hour"
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+type NetworkStorage interface {
+	// GetNetworkStats returns statistics aggregated by time buckets in descending order. Buckets start with the one containing start
+	// and end before end. Only the last limit buckets are returned.
+	GetNetworkStats(ctx context.Context, interval string, start, end time.Time, limit int) ([]*NetworkStats, error)
+}
+
+// BlockFees contains fees paid by operations of the block
+type BlockFees struct {
+	Level       int64
+	Hash        string
+	Predecessor string          // Canonical block at the previous level, empty for the genesis block
+	Fees        map[int][]int64 // Keyed by operation kind (see Operation* constants)
+}
+
+// FeeKinds are operation kinds having fees returned by GetBlockFees
+var FeeKinds = []int{
+	OperationTransaction,
+	OperationOrigination,
+	OperationDelegation,
+}
+
+type FeeStorage interface {
+	// GetBlockFees returns fees of canonical blocks within the level range (inclusive) in ascending order
+	GetBlockFees(ctx context.Context, from, to int64) ([]*BlockFees, error)
+}
+
+type Storage interface {
+	BalanceStorage
+	OperationStorage
+	ChainStorage
+	CycleStorage
+	VotingStorage
+	AccountStorage
+	ContractStorage
+	SearchStorage
+	StatsStorage
+	NetworkStorage
+	FeeStorage
 }
@@ -0,0 +1,72 @@
+package tezos
+
+import (
+	"errors"
+	"strings"
+)
+
+// AddressType is a type of the contract address
+type AddressType int
+
+const (
+	AddressEd25519 AddressType = iota
+	AddressSecp256k1
+	AddressP256
+	AddressOriginated
+)
+
+var addressPrefixes = map[AddressType]*Prefix{
+	AddressEd25519:    PrefixEd25519PublicKeyHash,
+	AddressSecp256k1:  PrefixSecp256k1PublicKeyHash,
+	AddressP256:       PrefixP256PublicKeyHash,
+	AddressOriginated: PrefixContractHash,
+}
+
+// ErrInvalidAddress is returned if the address prefix is not recognized
+var ErrInvalidAddress = errors.New("tezos: address must start with tz1, tz2, tz3 or KT1")
+
+// Address is a decoded implicit (tz1/tz2/tz3) or originated (KT1) contract address
+type Address struct {
+	Type AddressType
+	Hash [20]byte
+}
+
+// ParseAddress decodes and validates the contract address
+func ParseAddress(s string) (*Address, error) {
+	for t, p := range addressPrefixes {
+		if !strings.HasPrefix(s, p.Name) {
+			continue
+		}
+
+		payload, err := Decode(s, p)
+		if err != nil {
+			return nil, err
+		}
+
+		a := Address{Type: t}
+		copy(a.Hash[:], payload)
+		return &a, nil
+	}
+
+	return nil, ErrInvalidAddress
+}
+
+// Implicit returns true for tz1/tz2/tz3 addresses
+func (a *Address) Implicit() bool {
+	return a.Type != AddressOriginated
+}
+
+// String returns base58check encoded address
+func (a *Address) String() string {
+	return Encode(a.Hash[:], addressPrefixes[a.Type])
+}
+
+// Bytes returns 22 bytes long binary contract ID as used by the Tezos binary encoding
+// (tag 0 followed by the key hash tag and the hash for implicit accounts,
+// tag 1 followed by the hash and a padding byte for originated ones)
+func (a *Address) Bytes() []byte {
+	if a.Type == AddressOriginated {
+		return append(append([]byte{1}, a.Hash[:]...), 0)
+	}
+	return append([]byte{0, byte(a.Type)}, a.Hash[:]...)
+}
@@ -0,0 +1,110 @@
+package tezos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	ErrInvalidCharacter = errors.New("tezos: invalid base58 character")
+	ErrInvalidChecksum  = errors.New("tezos: invalid checksum")
+	ErrTooShort         = errors.New("tezos: data is too short")
+)
+
+var base58Index [256]int
+
+func init() {
+	for i := range base58Index {
+		base58Index[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		base58Index[base58Alphabet[i]] = i
+	}
+}
+
+var bigRadix = big.NewInt(58)
+
+// IsBase58 returns true if s contains only base58 characters
+func IsBase58(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if base58Index[s[i]] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Base58Decode decodes base58 encoded string using Bitcoin alphabet
+func Base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	for i := 0; i < len(s); i++ {
+		v := base58Index[s[i]]
+		if v < 0 {
+			return nil, ErrInvalidCharacter
+		}
+		n.Mul(n, bigRadix)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// Base58Encode encodes data using Bitcoin alphabet
+func Base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	var out []byte
+	mod := new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, bigRadix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+
+	for i := 0; i < len(data) && data[i] == 0; i++ {
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+func checksum(data []byte) []byte {
+	h := sha256.Sum256(data)
+	h = sha256.Sum256(h[:])
+	return h[:4]
+}
+
+// Base58CheckDecode decodes base58 encoded string and verifies its double SHA-256 checksum
+func Base58CheckDecode(s string) ([]byte, error) {
+	buf, err := Base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buf) < 4 {
+		return nil, ErrTooShort
+	}
+
+	data, sum := buf[:len(buf)-4], buf[len(buf)-4:]
+	if !bytes.Equal(checksum(data), sum) {
+		return nil, ErrInvalidChecksum
+	}
+
+	return data, nil
+}
+
+// Base58CheckEncode appends double SHA-256 checksum to data and encodes the result
+func Base58CheckEncode(data []byte) string {
+	return Base58Encode(append(append([]byte{}, data...), checksum(data)...))
+}
@@ -0,0 +1,70 @@
+// Package tezos implements validation and decoding of Tezos base58check encoded values and binary numeric fields
+package tezos
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Prefix describes a kind of base58check encoded value
+type Prefix struct {
+	Name   string // Human readable prefix, e.g. "tz1"
+	Bytes  []byte // Binary prefix
+	Length int    // Payload length
+}
+
+// See lib_crypto/base58.ml
+var (
+	PrefixEd25519PublicKeyHash   = &Prefix{"tz1", []byte{6, 161, 159}, 20}
+	PrefixSecp256k1PublicKeyHash = &Prefix{"tz2", []byte{6, 161, 161}, 20}
+	PrefixP256PublicKeyHash      = &Prefix{"tz3", []byte{6, 161, 164}, 20}
+	PrefixContractHash           = &Prefix{"KT1", []byte{2, 90, 121}, 20}
+	PrefixBlockHash              = &Prefix{"B", []byte{1, 52}, 32}
+	PrefixOperationHash          = &Prefix{"o", []byte{5, 116}, 32}
+	PrefixProtocolHash           = &Prefix{"P", []byte{2, 170}, 32}
+	PrefixChainID                = &Prefix{"Net", []byte{87, 82, 0}, 4}
+	PrefixEd25519PublicKey       = &Prefix{"edpk", []byte{13, 15, 37, 217}, 32}
+	PrefixSecp256k1PublicKey     = &Prefix{"sppk", []byte{3, 254, 226, 86}, 33}
+	PrefixP256PublicKey          = &Prefix{"p2pk", []byte{3, 178, 139, 127}, 33}
+)
+
+// Decode decodes base58check encoded value and verifies its prefix and length
+func Decode(s string, prefix *Prefix) ([]byte, error) {
+	if !strings.HasPrefix(s, prefix.Name) {
+		return nil, fmt.Errorf("tezos: %s value expected", prefix.Name)
+	}
+
+	data, err := Base58CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, prefix.Bytes) {
+		return nil, fmt.Errorf("tezos: invalid %s prefix", prefix.Name)
+	}
+
+	payload := data[len(prefix.Bytes):]
+	if len(payload) != prefix.Length {
+		return nil, fmt.Errorf("tezos: invalid %s payload length %d, expected %d", prefix.Name, len(payload), prefix.Length)
+	}
+
+	return payload, nil
+}
+
+// Encode encodes payload using base58check with the given prefix
+func Encode(payload []byte, prefix *Prefix) string {
+	return Base58CheckEncode(append(append([]byte{}, prefix.Bytes...), payload...))
+}
+
+// ValidateBlockHash returns nil if s is a valid block hash
+func ValidateBlockHash(s string) error {
+	_, err := Decode(s, PrefixBlockHash)
+	return err
+}
+
+// ValidateOperationHash returns nil if s is a valid operation hash
+func ValidateOperationHash(s string) error {
+	_, err := Decode(s, PrefixOperationHash)
+	return err
+}
@@ -0,0 +1,68 @@
+package tezos
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DecodeUnsignedLE decodes a hex dump of a little-endian arbitrary-precision unsigned integer, e.g. block_alpha.consumed_gas.
+// The empty string is decoded as zero.
+func DecodeUnsignedLE(s string) (*big.Int, error) {
+	buf, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("tezos: %v", err)
+	}
+
+	// big.Int expects big-endian bytes
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// Fitness is a decoded block fitness. The first component is the format version, the rest are
+// big-endian unsigned integers, e.g. the score of Emmy based protocols.
+type Fitness struct {
+	Version    int64      `json:"version"`
+	Components []*big.Int `json:"components"`
+	Raw        []string   `json:"raw"` // Hex encoded components
+}
+
+// FitnessSeparator separates hex encoded fitness components, see lib_base/fitness.ml
+const FitnessSeparator = "::"
+
+// DecodeFitness decodes a fitness printed as hex encoded byte sequences separated by FitnessSeparator, e.g. "01::000000000003a2c1"
+func DecodeFitness(s string) (*Fitness, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("tezos: empty fitness")
+	}
+
+	parts := strings.Split(s, FitnessSeparator)
+	f := Fitness{
+		Components: make([]*big.Int, 0, len(parts)-1),
+		Raw:        parts,
+	}
+
+	for i, p := range parts {
+		buf, err := hex.DecodeString(p)
+		if err != nil {
+			return nil, fmt.Errorf("tezos: fitness component %d: %v", i, err)
+		}
+
+		v := new(big.Int).SetBytes(buf)
+		if i == 0 {
+			if !v.IsInt64() {
+				return nil, fmt.Errorf("tezos: fitness version is too long: %s", p)
+			}
+			f.Version = v.Int64()
+			continue
+		}
+		f.Components = append(f.Components, v)
+	}
+
+	return &f, nil
+}
@@ -0,0 +1,142 @@
+package tezos
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestDecodeUnsignedLE(t *testing.T) {
+	tests := []struct {
+		src string
+		val string
+		err bool
+	}{
+		{src: "", val: "0"},
+		{src: "00", val: "0"},
+		{src: "01", val: "1"},
+		{src: "ff", val: "255"},
+		{src: "0001", val: "256"},
+		{src: "a08601", val: "100000"},
+		{src: "ffffffffffffffffff", val: "4722366482869645213695"},
+		{src: " 10 ", val: "16"},
+		{src: "1", err: true},
+		{src: "abc", err: true},
+		{src: "zz", err: true},
+	}
+
+	for _, test := range tests {
+		v, err := DecodeUnsignedLE(test.src)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: error expected, got %v", test.src, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		if v.String() != test.val {
+			t.Errorf("%q: expected %s, got %v", test.src, test.val, v)
+		}
+	}
+}
+
+func TestDecodeUnsignedLERoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "1", "255", "256", "65535", "8000000", "18446744073709551616", "340282366920938463463374607431768211455"} {
+		v, _ := new(big.Int).SetString(s, 10)
+
+		// Encode as little-endian
+		buf := v.Bytes()
+		for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+			buf[i], buf[j] = buf[j], buf[i]
+		}
+
+		res, err := DecodeUnsignedLE(hex.EncodeToString(buf))
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if res.Cmp(v) != 0 {
+			t.Errorf("%s: got %v", s, res)
+		}
+	}
+}
+
+func TestDecodeFitness(t *testing.T) {
+	tests := []struct {
+		src        string
+		version    int64
+		components []string
+		err        bool
+	}{
+		{src: "00", version: 0, components: []string{}},
+		{src: "01::000000000003a2c1", version: 1, components: []string{"238273"}},
+		{src: "02::00000001::0000000000000010", version: 2, components: []string{"1", "16"}},
+		{src: "01::", version: 1, components: []string{"0"}},
+		{src: "01::ffffffffffffffffff", version: 1, components: []string{"4722366482869645213695"}},
+		{src: "", err: true},
+		{src: "   ", err: true},
+		{src: "1::00", err: true},
+		{src: "01::abc", err: true},
+		{src: "01:00", err: true},
+		{src: "ffffffffffffffffff::00", err: true},
+	}
+
+	for _, test := range tests {
+		f, err := DecodeFitness(test.src)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: error expected, got %+v", test.src, f)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		if f.Version != test.version {
+			t.Errorf("%q: expected version %d, got %d", test.src, test.version, f.Version)
+		}
+		if len(f.Components) != len(test.components) {
+			t.Errorf("%q: expected %d components, got %d", test.src, len(test.components), len(f.Components))
+			continue
+		}
+		for i, c := range f.Components {
+			if c.String() != test.components[i] {
+				t.Errorf("%q: component %d: expected %s, got %v", test.src, i, test.components[i], c)
+			}
+		}
+	}
+}
+
+func TestDecodeFitnessRoundTrip(t *testing.T) {
+	for _, s := range []string{"00", "01::000000000003a2c1", "02::00000001::0000000000000010::ff"} {
+		f, err := DecodeFitness(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+
+		// Raw components are preserved and decode to the same values
+		res := ""
+		for i, p := range f.Raw {
+			if i != 0 {
+				res += FitnessSeparator
+			}
+			res += p
+
+			if i == 0 {
+				continue
+			}
+			buf, _ := hex.DecodeString(p)
+			if new(big.Int).SetBytes(buf).Cmp(f.Components[i-1]) != 0 {
+				t.Errorf("%q: component %d doesn't match %s", s, i-1, p)
+			}
+		}
+		if res != s {
+			t.Errorf("expected %q, got %q", s, res)
+		}
+	}
+}
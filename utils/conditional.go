@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// Validator describes the most recent block a response depends on
+type Validator struct {
+	Level     int64
+	Hash      string
+	Timestamp time.Time
+	Final     bool // The response can't be affected by new blocks
+}
+
+// ETag returns a strong entity tag derived from the block level and hash
+func (v *Validator) ETag() string {
+	return fmt.Sprintf("\"%d-%s\"", v.Level, v.Hash)
+}
+
+func etagMatch(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func notModified(r *http.Request, v *Validator, etag string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, etag)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !v.Timestamp.IsZero() && getEnricher(r.Context()) == nil {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !v.Timestamp.Truncate(time.Second).After(t)
+		}
+	}
+
+	return false
+}
+
+// ConditionalJSONResponse sets validator and caching headers and writes a JSON response or 304 Not Modified.
+// Final responses are marked immutable, other are cached for maxAge. Responses rewritten by the context's
+// Enricher are never immutable and their entity tags include the enricher version.
+func ConditionalJSONResponse(w http.ResponseWriter, r *http.Request, v interface{}, val *Validator, maxAge time.Duration) {
+	enricher := getEnricher(r.Context())
+
+	etag := val.ETag()
+	if enricher != nil {
+		etag = fmt.Sprintf("%s-%d\"", strings.TrimSuffix(etag, "\""), enricher.Version())
+	}
+
+	h := w.Header()
+	h.Set("ETag", etag)
+
+	// Block timestamps don't reflect enricher changes
+	if !val.Timestamp.IsZero() && enricher == nil {
+		h.Set("Last-Modified", val.Timestamp.UTC().Format(http.TimeFormat))
+	}
+
+	if val.Final && enricher == nil {
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", int(immutableMaxAge/time.Second)))
+	} else if maxAge > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge/time.Second)))
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+
+	if (r.Method == "GET" || r.Method == "HEAD") && notModified(r, val, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if enricher == nil {
+		JSONResponse(w, http.StatusOK, v)
+		return
+	}
+
+	body, err := json.Marshal(v)
+	if err == nil {
+		var version uint64
+		if body, version, err = enricher.Enrich(body); err == nil {
+			// The data may have changed since the entity tag has been computed
+			h.Set("ETag", fmt.Sprintf("%s-%d\"", strings.TrimSuffix(val.ETag(), "\""), version))
+		}
+	}
+	if err != nil {
+		for _, k := range []string{"ETag", "Cache-Control"} {
+			h.Del(k)
+		}
+		JSONError(w, r, err)
+		return
+	}
+
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	// Same as json.Encoder
+	w.Write(append(body, '\n'))
+}
@@ -0,0 +1,76 @@
+package utils
+
+import "context"
+
+type requestIDKey struct{}
+type errorOptionsKey struct{}
+type identityKey struct{}
+type enricherKey struct{}
+
+// WithRequestID returns a copy of the context carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by the context or an empty string
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ErrorOptions control error responses rendering
+type ErrorOptions struct {
+	Production bool   // Hide internal error details
+	DocsURL    string // Error codes documentation URL
+}
+
+// WithErrorOptions returns a copy of the context carrying error rendering options
+func WithErrorOptions(ctx context.Context, opt *ErrorOptions) context.Context {
+	return context.WithValue(ctx, errorOptionsKey{}, opt)
+}
+
+func getErrorOptions(ctx context.Context) *ErrorOptions {
+	if opt, ok := ctx.Value(errorOptionsKey{}).(*ErrorOptions); ok {
+		return opt
+	}
+	return &ErrorOptions{}
+}
+
+// Identity describes an authenticated client
+type Identity struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"` // Client certificate subject
+}
+
+// WithIdentity returns a copy of the context carrying the client identity
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// GetIdentity returns the client identity carried by the context or nil for anonymous clients
+func GetIdentity(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// Enricher rewrites successful JSON response bodies, e.g. adds address labels
+type Enricher interface {
+	Enrich(body []byte) ([]byte, uint64, error) // Returns the version of the data used along with the body
+	Version() uint64                            // Changes whenever the output may change
+}
+
+// WithEnricher returns a copy of the context carrying the response enricher
+func WithEnricher(ctx context.Context, e Enricher) context.Context {
+	return context.WithValue(ctx, enricherKey{}, e)
+}
+
+// WithoutEnricher returns a copy of the context without the response enricher. Used by handlers whose responses
+// can't be rewritten by it, e.g. contain no addresses, to save the cost of rewriting.
+func WithoutEnricher(ctx context.Context) context.Context {
+	return context.WithValue(ctx, enricherKey{}, nil)
+}
+
+func getEnricher(ctx context.Context) Enricher {
+	e, _ := ctx.Value(enricherKey{}).(Enricher)
+	return e
+}
@@ -8,10 +8,14 @@ import (
 	errorsv2 "github.com/pkg/errors"
 )
 
-type errorResponse struct {
-	Error string `json:"error,omitempty"`
-	Code  string `json:"code,omitempty"`
-	Cause string `json:"cause,omitempty"`
+// ErrorResponse is a JSON error body
+type ErrorResponse struct {
+	Error     string               `json:"error,omitempty"`
+	Code      string               `json:"code,omitempty"`
+	Cause     string               `json:"cause,omitempty"`
+	Fields    []*errors.FieldError `json:"fields,omitempty"`
+	RequestID string               `json:"request_id,omitempty"`
+	Docs      string               `json:"docs,omitempty"`
 }
 
 func JSONResponse(w http.ResponseWriter, status int, v interface{}) {
@@ -20,7 +24,7 @@ func JSONResponse(w http.ResponseWriter, status int, v interface{}) {
 	json.NewEncoder(w).Encode(v)
 }
 
-func JSONError(w http.ResponseWriter, err error) {
+func JSONError(w http.ResponseWriter, r *http.Request, err error) {
 	var code errors.Code
 
 	if e, ok := err.(errors.Error); ok {
@@ -29,15 +33,30 @@ func JSONError(w http.ResponseWriter, err error) {
 		code = errors.CodeUnknown
 	}
 
-	res := errorResponse{
-		Error: err.Error(),
-		Code:  code.String(),
+	opt := getErrorOptions(r.Context())
+
+	res := ErrorResponse{
+		Error:     err.Error(),
+		Code:      code.String(),
+		RequestID: RequestID(r.Context()),
+		Docs:      opt.DocsURL,
+	}
+
+	if v, ok := err.(*errors.ValidationError); ok {
+		res.Error = v.Message
+		res.Fields = v.Fields
 	}
 
 	if cause := errorsv2.Cause(err); cause != err {
 		res.Cause = cause.Error()
 	}
 
+	// Internal errors may contain SQL details
+	if opt.Production && code.Status() >= http.StatusInternalServerError {
+		res.Error = http.StatusText(code.Status())
+		res.Cause = ""
+	}
+
 	JSONResponse(w, code.Status(), &res)
 }
 